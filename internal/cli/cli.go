@@ -0,0 +1,353 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cli implements the fake-api-client command line tool.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"flag"
+	"fmt"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// exit codes returned by Run, the api errors are mapped
+// from the ResponseError status code.
+const (
+	ExitOK           = 0
+	ExitError        = 1
+	ExitUsage        = 2
+	ExitInvalid      = 3
+	ExitNotFound     = 4
+	ExitConflict     = 5
+	ExitUnauthorized = 6
+	ExitRateLimited  = 7
+	ExitServer       = 8
+	ExitCanceled     = 9
+)
+
+const usage = `Usage: fake-api-client [command]
+
+Commands:
+  version                              print the client version
+  accounts create -f <file>            create the account from the json file
+  accounts get <id>                    print the account with id
+  accounts list [--page <n>] [--page-size <n>] [--all] [--filter <key>=<value>]...
+                                       print the accounts, filter keys are bank_id,
+                                       account_number, iban, country and customer_id
+  accounts delete <id> [--version <n>] delete the account, using its current version
+                                       if --version is not given
+
+Common flags:
+  --output json|table|yaml             output format, default json
+  --base-url <url>                     base api url, default BASE_API_URL
+`
+
+// command struct keeps the objects used by a command.
+type command struct {
+	ctx     context.Context
+	version string
+	stdout  io.Writer
+	stderr  io.Writer
+	flags   *flag.FlagSet
+	output  string
+	baseURL string
+}
+
+// Run function runs the command described by args and returns the process exit code.
+// version is the client version, the command output is written to stdout
+// and the errors are written to stderr.
+func Run(version string, args []string, stdout, stderr io.Writer) int {
+	cmd := &command{ctx: context.Background(), version: version, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return ExitUsage
+	}
+	if args[0] == "version" {
+		fmt.Fprintf(stdout, "App Version: %s\n", version)
+		return ExitOK
+	}
+	if args[0] != "accounts" || len(args) < 2 {
+		fmt.Fprint(stderr, usage)
+		return ExitUsage
+	}
+
+	switch args[1] {
+	case "create":
+		return cmd.create(args[2:])
+	case "get":
+		return cmd.get(args[2:])
+	case "list":
+		return cmd.list(args[2:])
+	case "delete":
+		return cmd.delete(args[2:])
+	}
+	fmt.Fprint(stderr, usage)
+	return ExitUsage
+}
+
+// create method creates the account from the -f json file.
+func (c *command) create(args []string) int {
+	file := c.newFlags("create").String("f", "", "account json file")
+	if _, code := c.parse(args, 0); code != ExitOK {
+		return code
+	}
+	if len(*file) == 0 {
+		return c.usageError("create requires -f <file>")
+	}
+
+	b, err := os.ReadFile(*file)
+	if err != nil {
+		return c.fail(err)
+	}
+	acc := model.Account{}
+	if err = json.Unmarshal(b, &acc); err != nil {
+		return c.fail(fmt.Errorf("fail to decode account file %s: %w", *file, err))
+	}
+
+	res, err := c.accounts().CreateCtx(c.ctx, acc)
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.print([]model.Account{*res.(*model.Account)}, false)
+}
+
+// get method prints the account with the id argument.
+func (c *command) get(args []string) int {
+	c.newFlags("get")
+	pos, code := c.parse(args, 1)
+	if code != ExitOK {
+		return code
+	}
+
+	res, err := c.accounts().ListByCtx(c.ctx, pos[0])
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.print([]model.Account{*res.(*model.Account)}, false)
+}
+
+// list method prints the accounts page, or all accounts if --all is given.
+func (c *command) list(args []string) int {
+	fs := c.newFlags("list")
+	page := fs.Int("page", 0, "page number")
+	pageSize := fs.Int("page-size", 0, "page size, default HTTP_DEFAULT_PAGE_SIZE")
+	all := fs.Bool("all", false, "list all pages")
+	filter := filterFlag{}
+	fs.Var(&filter, "filter", "filter as key=value, can be repeated")
+	if _, code := c.parse(args, 0); code != ExitOK {
+		return code
+	}
+
+	accList := []model.Account{}
+	opts := service.ListOptions{PageNumber: *page, PageSize: *pageSize, Filter: filter.accountFilter()}
+	if *all {
+		it := c.accounts().IterateWith(c.ctx, opts)
+		for it.Next() {
+			accList = append(accList, it.Value())
+		}
+		if err := it.Err(); err != nil {
+			return c.fail(err)
+		}
+		return c.print(accList, true)
+	}
+
+	if opts.PageSize == 0 {
+		opts.PageSize, _ = strconv.Atoi(c.client().PageSize)
+	}
+	resList, err := c.accounts().ListWith(c.ctx, opts)
+	if err != nil {
+		return c.fail(err)
+	}
+	for _, res := range resList {
+		accList = append(accList, res.(model.Account))
+	}
+	return c.print(accList, true)
+}
+
+// delete method deletes the account with the id argument.
+func (c *command) delete(args []string) int {
+	version := c.newFlags("delete").Int("version", -1, "account version, default the current version")
+	pos, code := c.parse(args, 1)
+	if code != ExitOK {
+		return code
+	}
+
+	var err error
+	if *version < 0 {
+		err = c.accounts().DeleteLatestCtx(c.ctx, pos[0])
+	} else {
+		err = c.accounts().DeleteByVersionCtx(c.ctx, pos[0], *version)
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+	return ExitOK
+}
+
+// newFlags method creates the command flag set with the common flags.
+func (c *command) newFlags(name string) *flag.FlagSet {
+	c.flags = flag.NewFlagSet(name, flag.ContinueOnError)
+	c.flags.SetOutput(c.stderr)
+	c.flags.StringVar(&c.output, "output", "json", "output format: json, table or yaml")
+	c.flags.StringVar(&c.baseURL, "base-url", "", "base api url")
+	return c.flags
+}
+
+// parse method parses args allowing flags after the positional arguments
+// and checks that exactly n positional arguments were given.
+func (c *command) parse(args []string, n int) ([]string, int) {
+	pos := []string{}
+	for {
+		if err := c.flags.Parse(args); err != nil {
+			return nil, ExitUsage
+		}
+		args = c.flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		pos = append(pos, args[0])
+		args = args[1:]
+	}
+
+	if len(pos) != n {
+		return nil, c.usageError(fmt.Sprintf("%s requires %d argument(s), given: %d", c.flags.Name(), n, len(pos)))
+	}
+	if c.output != "json" && c.output != "table" && c.output != "yaml" {
+		return nil, c.usageError("unknown output format: " + c.output)
+	}
+	return pos, ExitOK
+}
+
+// client method returns the client using the --base-url flag.
+func (c *command) client() *client.Client {
+	if len(c.baseURL) == 0 {
+		return client.New()
+	}
+	return client.New(client.WithBaseURL(c.baseURL))
+}
+
+// accounts method returns the account service using the command client.
+func (c *command) accounts() service.Account {
+	return service.NewAccount(c.client())
+}
+
+// print method writes accList using the output format,
+// if list is false only the first account is written.
+func (c *command) print(accList []model.Account, list bool) int {
+	var data interface{} = accList
+	if !list {
+		data = accList[0]
+	}
+
+	var err error
+	switch c.output {
+	case "table":
+		err = writeTable(c.stdout, accList)
+	case "yaml":
+		err = writeYAML(c.stdout, data)
+	default:
+		err = writeJSON(c.stdout, data)
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+	return ExitOK
+}
+
+// usageError method writes msg and the usage to stderr.
+func (c *command) usageError(msg string) int {
+	fmt.Fprintf(c.stderr, "%s\n\n%s", msg, usage)
+	return ExitUsage
+}
+
+// fail method writes err to stderr and returns its exit code.
+func (c *command) fail(err error) int {
+	fmt.Fprintf(c.stderr, "error: %s\n", err)
+	return ExitCode(err)
+}
+
+// ExitCode function returns the exit code of err.
+func ExitCode(err error) int {
+	var vErr errors.ValidationError
+	var cErr errors.CanceledError
+	switch {
+	case err == nil:
+		return ExitOK
+	case goerrors.As(err, &vErr):
+		return ExitInvalid
+	case goerrors.As(err, &cErr):
+		return ExitCanceled
+	case goerrors.Is(err, errors.ErrNotFound):
+		return ExitNotFound
+	case goerrors.Is(err, errors.ErrConflict):
+		return ExitConflict
+	case goerrors.Is(err, errors.ErrUnauthorized):
+		return ExitUnauthorized
+	case goerrors.Is(err, errors.ErrRateLimited):
+		return ExitRateLimited
+	case goerrors.Is(err, errors.ErrServer), goerrors.Is(err, errors.ErrCircuitOpen):
+		return ExitServer
+	}
+
+	var rErr errors.ResponseError
+	if goerrors.As(err, &rErr) && rErr.StatusCode >= 400 && rErr.StatusCode < 500 {
+		return ExitInvalid
+	}
+	return ExitError
+}
+
+// filterFlag type is the repeatable --filter key=value flag.
+type filterFlag map[string][]string
+
+// String method returns the flag value.
+func (f filterFlag) String() string {
+	return fmt.Sprint(map[string][]string(f))
+}
+
+// Set method adds a key=value filter, the value can contain more values separated by comma.
+func (f filterFlag) Set(v string) error {
+	kv := strings.SplitN(v, "=", 2)
+	if len(kv) != 2 || len(kv[1]) == 0 {
+		return fmt.Errorf("filter should be key=value: %s", v)
+	}
+
+	switch kv[0] {
+	case "bank_id", "account_number", "iban", "country", "customer_id":
+		f[kv[0]] = append(f[kv[0]], strings.Split(kv[1], ",")...)
+		return nil
+	}
+	return fmt.Errorf("unknown filter key: %s", kv[0])
+}
+
+// accountFilter method returns the AccountFilter of the flag values.
+func (f filterFlag) accountFilter() service.AccountFilter {
+	return service.AccountFilter{
+		BankID:        f["bank_id"],
+		AccountNumber: f["account_number"],
+		Iban:          f["iban"],
+		Country:       f["country"],
+		CustomerID:    f["customer_id"],
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"gopkg.in/yaml.v3"
+	"io"
+	"text/tabwriter"
+)
+
+// writeJSON function writes data as indented json to w.
+func writeJSON(w io.Writer, data interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+// writeYAML function writes data as yaml to w.
+// The data is converted using its json form, so the yaml keys
+// are the same as the api json keys.
+func writeYAML(w io.Writer, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	var v interface{}
+	if err = json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err = enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeTable function writes the main accList account fields as a table to w.
+func writeTable(w io.Writer, accList []model.Account) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tORGANISATION ID\tVERSION\tCOUNTRY\tBANK ID\tACCOUNT NUMBER\tIBAN\tSTATUS")
+
+	for _, acc := range accList {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			acc.ID, acc.OrganisationID, acc.Version, acc.Attributes.Country,
+			acc.Attributes.BankID, acc.Attributes.AccountNumber, acc.Attributes.Iban, acc.Attributes.Status)
+	}
+	return tw.Flush()
+}
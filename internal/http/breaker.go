@@ -0,0 +1,239 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"fmt"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState type describes the state of a CircuitBreaker.
+type CircuitState int
+
+// circuit breaker states.
+// A closed circuit sends every request, an open circuit fails every request
+// without sending it and a half-open circuit sends a few trial requests
+// to check if the server recovered.
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+// String method returns the state name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("unknown(%d)", int(s))
+}
+
+// CircuitBreakerSettings struct describes when a CircuitBreaker changes its state.
+// The closed circuit opens after ConsecutiveFailures consecutive failed requests,
+// or when at least MinRequests requests were sent in the last Window and the ratio
+// of failed requests is at least FailureRatio. A zero threshold is not used.
+// The open circuit becomes half-open after CoolDown, the half-open circuit allows
+// HalfOpenRequests trial requests and closes if all of them succeed,
+// or opens again if one of them fails.
+// A request fails if no response was received or the response status code is 5xx or 429.
+// OnStateChange, if it is not nil, is called after every state change.
+type CircuitBreakerSettings struct {
+	ConsecutiveFailures int
+	FailureRatio        float64
+	MinRequests         int
+	Window              time.Duration
+	CoolDown            time.Duration
+	HalfOpenRequests    int
+	OnStateChange       func(from, to CircuitState)
+}
+
+// DefaultCircuitBreakerSettings function returns CircuitBreakerSettings that open
+// the circuit after 5 consecutive failures or when half of at least 10 requests
+// from the last minute failed, with 30 seconds cool-down and 1 trial request.
+func DefaultCircuitBreakerSettings() CircuitBreakerSettings {
+	return CircuitBreakerSettings{
+		ConsecutiveFailures: 5,
+		FailureRatio:        0.5,
+		MinRequests:         10,
+		Window:              time.Minute,
+		CoolDown:            30 * time.Second,
+		HalfOpenRequests:    1,
+	}
+}
+
+// CircuitBreaker struct is used to fail fast when the server is down,
+// instead of waiting for every request time out.
+// It is safe to be used by more goroutines.
+type CircuitBreaker struct {
+	settings CircuitBreakerSettings
+
+	mu          sync.Mutex
+	state       CircuitState
+	openedAt    time.Time
+	windowStart time.Time
+	requests    int
+	failures    int
+	consecutive int
+	trials      int
+	successes   int
+	changes     [][2]CircuitState
+}
+
+// NewCircuitBreaker function returns a closed CircuitBreaker using s settings.
+// If s.HalfOpenRequests is less than 1 it is set to 1.
+func NewCircuitBreaker(s CircuitBreakerSettings) *CircuitBreaker {
+	if s.HalfOpenRequests < 1 {
+		s.HalfOpenRequests = 1
+	}
+	return &CircuitBreaker{settings: s, windowStart: time.Now()}
+}
+
+// State method returns the current circuit breaker state.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.coolDown(time.Now())
+	return b.state
+}
+
+// allow method checks if a request can be sent, if the circuit is open
+// or the half-open circuit has no trial requests left CircuitOpenError is returned.
+func (b *CircuitBreaker) allow(req *http.Request) error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	now := time.Now()
+	b.coolDown(now)
+
+	switch b.state {
+	case CircuitOpen:
+		return b.openError(req, b.openedAt.Add(b.settings.CoolDown).Sub(now))
+	case CircuitHalfOpen:
+		if b.trials >= b.settings.HalfOpenRequests {
+			return b.openError(req, 0)
+		}
+		b.trials++
+	}
+	return nil
+}
+
+// record method records the result of an allowed request.
+func (b *CircuitBreaker) record(res *http.Response, err error) {
+	failed := err != nil || res.StatusCode >= http.StatusInternalServerError ||
+		res.StatusCode == http.StatusTooManyRequests
+
+	b.mu.Lock()
+	defer b.unlock()
+
+	now := time.Now()
+	switch b.state {
+	case CircuitHalfOpen:
+		if failed {
+			b.setState(CircuitOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.settings.HalfOpenRequests {
+			b.setState(CircuitClosed, now)
+		}
+	case CircuitClosed:
+		if b.settings.Window > 0 && now.Sub(b.windowStart) > b.settings.Window {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+		b.requests++
+		b.consecutive++
+		if failed {
+			b.failures++
+		} else {
+			b.consecutive = 0
+		}
+		if b.shouldOpen() {
+			b.setState(CircuitOpen, now)
+		}
+	}
+}
+
+// shouldOpen method checks the closed circuit thresholds.
+func (b *CircuitBreaker) shouldOpen() bool {
+	s := b.settings
+	if s.ConsecutiveFailures > 0 && b.consecutive >= s.ConsecutiveFailures {
+		return true
+	}
+	return s.FailureRatio > 0 && b.requests >= s.MinRequests &&
+		float64(b.failures)/float64(b.requests) >= s.FailureRatio
+}
+
+// coolDown method changes the open circuit to half-open after the cool-down.
+func (b *CircuitBreaker) coolDown(now time.Time) {
+	if b.state == CircuitOpen && now.Sub(b.openedAt) >= b.settings.CoolDown {
+		b.setState(CircuitHalfOpen, now)
+	}
+}
+
+// setState method changes the circuit state, resets the counters
+// and keeps the change for OnStateChange callback.
+func (b *CircuitBreaker) setState(to CircuitState, now time.Time) {
+	from := b.state
+	b.state = to
+	b.windowStart, b.requests, b.failures, b.consecutive = now, 0, 0, 0
+	b.trials, b.successes = 0, 0
+	if to == CircuitOpen {
+		b.openedAt = now
+	}
+
+	b.changes = append(b.changes, [2]CircuitState{from, to})
+}
+
+// release method gives back the trial request of a canceled or not sent request,
+// so the half-open circuit can send another trial request.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.unlock()
+
+	if b.state == CircuitHalfOpen && b.trials > 0 {
+		b.trials--
+	}
+}
+
+// unlock method unlocks the circuit breaker and calls OnStateChange callback
+// for every state change, the callback is called without holding the lock
+// so it can use the circuit breaker.
+func (b *CircuitBreaker) unlock() {
+	changes := b.changes
+	b.changes = nil
+	b.mu.Unlock()
+
+	if b.settings.OnStateChange != nil {
+		for _, c := range changes {
+			b.settings.OnStateChange(c[0], c[1])
+		}
+	}
+}
+
+// openError method returns the CircuitOpenError for req.
+func (b *CircuitBreaker) openError(req *http.Request, retryAfter time.Duration) error {
+	return errors.CircuitOpenError{
+		Message:    fmt.Sprintf("%s request to %s was not sent, circuit breaker is %s", req.Method, req.URL, b.state),
+		RetryAfter: retryAfter}
+}
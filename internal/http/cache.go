@@ -0,0 +1,244 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Cache struct is a read-through cache of resource responses, shared by all goroutines
+// that send requests using the same client. Only the requests sent using
+// SendCachedRequest are cached, like the resource fetch requests.
+// An entry is fresh for TTL after it was received, a stale entry with an ETag
+// is revalidated using If-None-Match, otherwise it is requested again.
+// At most MaxEntries entries are kept, the least recently used entry is evicted first.
+// The entry of a resource is removed when the client sends any other
+// than GET request to the resource url, like delete and update requests.
+// A response received after its resource was removed is not cached, so a request
+// sent before a delete or update request cannot cache the previous resource.
+type Cache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	stats      CacheStats
+
+	// generation is incremented every time entries are removed by invalidate or Purge,
+	// removed keeps the generation of the removed keys and the responses of requests
+	// started before floor generation are not cached.
+	generation uint64
+	removed    map[string]uint64
+	floor      uint64
+}
+
+// CacheStats struct describes the Cache usage.
+// Hits is the number of fresh entries returned without a request,
+// Revalidations is the number of stale entries returned after a not modified response,
+// Misses is the number of responses received from the server.
+type CacheStats struct {
+	Hits          int
+	Misses        int
+	Revalidations int
+	Evictions     int
+	Entries       int
+}
+
+// maxRemovedKeys is the maximum number of removed keys kept by a Cache,
+// when there are more keys they are forgotten and the responses of all
+// requests started before are not cached.
+const maxRemovedKeys = 1024
+
+// cacheEntry struct keeps a cached response body.
+type cacheEntry struct {
+	key     string
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// response method returns a copy of res with expCode status code and the entry body.
+func (e *cacheEntry) response(res *http.Response, expCode int) *http.Response {
+	cached := *res
+	cached.StatusCode = expCode
+	cached.Status = fmt.Sprintf("%d %s", expCode, http.StatusText(expCode))
+	cached.Body = io.NopCloser(bytes.NewReader(e.body))
+	return &cached
+}
+
+// NewCache function returns a Cache keeping the entries fresh for ttl,
+// if ttl is 0 every entry is revalidated before it is used.
+// If maxEntries is 0 or less the number of entries is not limited.
+func NewCache(ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+		removed:    map[string]uint64{},
+	}
+}
+
+// Stats method returns the Cache usage statistics.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	s.Entries = c.lru.Len()
+	return s
+}
+
+// Purge method removes all entries.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]*list.Element{}
+	c.lru.Init()
+	c.generation++
+	c.removed = map[string]uint64{}
+	c.floor = c.generation
+}
+
+// get method returns a copy of the req entry, or nil if req is not cached,
+// the current generation, that should be given to put, and if the entry is fresh.
+// A fresh entry is counted as a hit, otherwise if it has an ETag
+// the If-None-Match header is added to req. The copy is used to answer
+// a not modified response even if the entry is removed in the meantime.
+func (c *Cache) get(req *http.Request) (*cacheEntry, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, found := c.entries[cacheKey(req)]
+	if !found {
+		return nil, c.generation, false
+	}
+	c.lru.MoveToFront(el)
+	e := *el.Value.(*cacheEntry)
+	if time.Now().Before(e.expires) {
+		c.stats.Hits++
+		return &e, c.generation, true
+	}
+	if len(e.etag) != 0 {
+		req.Header.Set("If-None-Match", e.etag)
+	}
+	return &e, c.generation, false
+}
+
+// put method caches the res response of req if its status code is expCode and returns
+// the response that should be used instead of res. A not modified response is replaced
+// by an expCode response with the stale entry body, stale and gen are the entry
+// and the generation returned by get. If the req resource was removed after gen
+// the response is returned but it is not cached.
+func (c *Cache) put(req *http.Request, res *http.Response, expCode int, stale *cacheEntry, gen uint64) (*http.Response, error) {
+	key := cacheKey(req)
+
+	if res.StatusCode == http.StatusNotModified && stale != nil {
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+
+		e := &cacheEntry{key: key, body: stale.body, etag: stale.etag, expires: time.Now().Add(c.ttl)}
+		if etag := res.Header.Get("ETag"); len(etag) != 0 {
+			e.etag = etag
+		}
+		c.mu.Lock()
+		c.stats.Revalidations++
+		c.store(e, gen)
+		c.mu.Unlock()
+		return e.response(res, expCode), nil
+	}
+
+	if res.StatusCode != expCode {
+		c.mu.Lock()
+		c.stats.Misses++
+		c.remove(key)
+		c.mu.Unlock()
+		return res, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, errors.RequestError{
+			Message:  fmt.Sprintf("fail to read %s response body: %s", req.Method, err),
+			CausedBy: err}
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.mu.Lock()
+	c.stats.Misses++
+	c.store(&cacheEntry{key: key, body: body, etag: res.Header.Get("ETag"), expires: time.Now().Add(c.ttl)}, gen)
+	c.mu.Unlock()
+	return res, nil
+}
+
+// store method adds or replaces the e entry and evicts the least recently used entry
+// if there are more than maxEntries entries. If the e resource was removed after gen
+// generation the entry is not stored. The caller should hold the lock.
+func (c *Cache) store(e *cacheEntry, gen uint64) {
+	if gen < c.floor || c.removed[e.key] > gen {
+		return
+	}
+
+	if el, found := c.entries[e.key]; found {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.entries[e.key] = c.lru.PushFront(e)
+	if c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back().Value.(*cacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// invalidate method removes the entry of the req resource.
+func (c *Cache) invalidate(req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cacheKey(req)
+	c.remove(key)
+	c.generation++
+	c.removed[key] = c.generation
+	if len(c.removed) > maxRemovedKeys {
+		c.removed = map[string]uint64{}
+		c.floor = c.generation
+	}
+}
+
+// remove method removes the entry with key, the caller should hold the lock.
+func (c *Cache) remove(key string) {
+	if el, found := c.entries[key]; found {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+// cacheKey function returns the cache key of req, the url without query params.
+func cacheKey(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery = ""
+	return u.String()
+}
@@ -15,78 +15,301 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/pancudaniel7/fake-api-client/configs"
 	"github.com/pancudaniel7/fake-api-client/pkg/errors"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"sync"
+	"time"
 )
 
-// An clientAPI represents the struct type used
-// to create singleton http client object.
-type clientAPI struct {
-	HTTPClient *http.Client
+// A Client represents the struct type used to communicate
+// using http with the main server.
+// Every Client keeps its own http client, base api url,
+// default page size and record version, so more than one
+// Client can be used in the same process.
+// If RetryPolicy is nil every request is sent only once.
+// If Authenticator is not nil it is used to add credentials to every request attempt.
+// If Logging is not nil every request attempt is logged.
+// If Metrics is not nil every request is observed by it.
+// If RateLimiter is not nil every request attempt waits for it.
+// If CircuitBreaker is not nil it fails the request attempts while it is open.
+// If Tracing is not nil every request and request attempt is traced.
+// If Cache is not nil the requests sent using SendCachedRequest are cached.
+type Client struct {
+	HTTPClient     *http.Client
+	BaseURL        string
+	PageSize       string
+	RecordVersion  string
+	RetryPolicy    *RetryPolicy
+	Authenticator  Authenticator
+	Logging        *Logging
+	Metrics        MetricsCollector
+	RateLimiter    *RateLimiter
+	CircuitBreaker *CircuitBreaker
+	Tracing        *Tracing
+	Cache          *Cache
+}
+
+// Authenticator interface is used to add credentials to requests,
+// like bearer tokens or http signatures.
+// Authenticate can read the request body using req.GetBody.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
 }
 
 var (
 	once sync.Once
-	c    *clientAPI
+	c    *Client
 )
 
-// APIClient function return the clientAPI singleton object.
+// APIClient function return the Client singleton object.
 // The object is used to communicate using http with the main server.
 // APIClient is initialised once (lazy load) when it used for the first time,
-// also it contains HttpClientTimeout property.
-func APIClient() *clientAPI {
+// using the library properties as values.
+func APIClient() *Client {
 	once.Do(func() {
-		c = &clientAPI{
-			HTTPClient: &http.Client{
-				Timeout: configs.Properties().HttpClientTimeout,
-			},
-		}
+		c = NewClient()
 	})
 	return c
 }
 
+// NewClient function return a new Client object initialised
+// with the library properties values.
+// The returned object can be customised before it is used.
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: configs.Properties().HttpClientTimeout,
+		},
+		BaseURL:       configs.Properties().BaseAPIURL,
+		PageSize:      configs.Properties().HttpDefaultPageSize,
+		RecordVersion: configs.Properties().HttpRecordVersion,
+	}
+}
+
 // SendRequest method can send http requests and return http responses.
-// The method uses req as request object.
+// The method uses req as request object, if the req context is canceled
+// or its deadline is exceeded the method will return CanceledError.
+// The request is retried regarding the client RetryPolicy.
 // expCode is used to verify if the response is the correct one,
 // if the response dose not contains the expected status code the method
 // will return ErrorResponse containing description about the error.
 // the resData object is used to receive response body data or to send request body data.
 // if response cannot be decoded the method will return SyntaxError.
-func (c *clientAPI) SendRequest(req *http.Request, expCode int, resData interface{}) error {
+func (c *Client) SendRequest(req *http.Request, expCode int, resData interface{}) error {
+	return c.send(req, expCode, &Body{Data: resData}, false)
+}
+
+// SendCachedRequest method is the SendRequest method used for GET requests
+// whose response can be kept by the client Cache.
+// If the client Cache has a fresh response no request is sent,
+// if the client Cache is nil the method is the SendRequest method.
+func (c *Client) SendCachedRequest(req *http.Request, expCode int, resData interface{}) error {
+	if c.Cache == nil || req.Method != http.MethodGet {
+		return c.SendRequest(req, expCode, resData)
+	}
+
+	return c.send(req, expCode, &Body{Data: resData}, true)
+}
+
+// SendListRequest method is the SendRequest method used for list requests,
+// it also returns the response pagination links.
+// If the response does not contain links an empty Links object is returned.
+func (c *Client) SendListRequest(req *http.Request, expCode int, resData interface{}) (Links, error) {
+	fullResponse := &Body{Data: resData, Links: &Links{}}
+	if err := c.send(req, expCode, fullResponse, false); err != nil || fullResponse.Links == nil {
+		return Links{}, err
+	}
+	return *fullResponse.Links, nil
+}
+
+// send method sends req and decodes the response body in to fullResponse
+// if fullResponse Data is not nil. If cached is true the response is kept by the client Cache
+// and a fresh cached response is used without sending req, it is observed
+// by the client Metrics and Tracing as a cache hit. Any other than GET request
+// removes the resource from the client Cache.
+func (c *Client) send(req *http.Request, expCode int, fullResponse *Body, cached bool) (err error) {
 	req.Header.Set("Accept", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
+	var entry *cacheEntry
+	var gen uint64
+	hit := false
+	if cached {
+		entry, gen, hit = c.Cache.get(req)
+	}
+
+	start := time.Now()
+	status := 0
+
+	req, end := c.Tracing.startOperation(req)
+	defer func() {
+		end(status, err)
+	}()
+	if c.Metrics != nil {
+		defer func() {
+			c.Metrics.ObserveRequest(RequestObservation{
+				Operation:  Operation(req.Context()),
+				Method:     req.Method,
+				StatusCode: status,
+				Duration:   time.Since(start),
+				Err:        err,
+				CacheHit:   hit})
+		}()
+	}
+
+	if c.Cache != nil && req.Method != http.MethodGet {
+		defer c.Cache.invalidate(req)
+	}
+
+	var res *http.Response
+	if hit {
+		c.Tracing.markCacheHit(req)
+		res = entry.response(&http.Response{Header: http.Header{}, Request: req}, expCode)
+	} else if res, err = c.do(req); err != nil {
 		return err
 	}
+	status = res.StatusCode
+	if cached && !hit {
+		if res, err = c.Cache.put(req, res, expCode, entry, gen); err != nil {
+			return handleContextError(req, err)
+		}
+	}
 	defer res.Body.Close()
 
 	if err = handleExpectedStatusCode(*res, expCode); err != nil {
 		return err
 	}
 
-	fullResponse := Body{
-		Data: resData,
+	if fullResponse.Data != nil {
+		if err = json.NewDecoder(res.Body).Decode(fullResponse); err != nil {
+			return handleContextError(req, err)
+		}
+	}
+	return nil
+}
+
+// do method sends req until it succeeds or the client RetryPolicy
+// does not allow any other attempt, the last attempt response or error is returned.
+// Between attempts the method waits the RetryPolicy delay and before every attempt
+// it checks the CircuitBreaker and then waits for the RateLimiter, if the req context
+// is done while waiting CanceledError is returned.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	p := c.RetryPolicy
+	attempts := p.attempts(req)
+	if req.Body != nil && req.GetBody == nil {
+		attempts = 1
 	}
 
-	if resData != nil {
-		if err = json.NewDecoder(res.Body).Decode(&fullResponse); err != nil {
-			return err
+	for n := 1; ; n++ {
+		if n > 1 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, errors.RequestError{
+					Message:  fmt.Sprintf("fail to rewind %s request body: %s", req.Method, err),
+					CausedBy: err}
+			}
+			req.Body = body
+		}
+
+		// the circuit is checked first, so a rejected request does not
+		// spend rate limiter tokens or sign the request.
+		if c.CircuitBreaker != nil {
+			if err := c.CircuitBreaker.allow(req); err != nil {
+				return nil, err
+			}
+		}
+
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(req.Context(), Operation(req.Context())); err != nil {
+				if c.CircuitBreaker != nil {
+					c.CircuitBreaker.release()
+				}
+				return nil, handleContextError(req, err)
+			}
+		}
+
+		if c.Authenticator != nil {
+			if err := c.Authenticator.Authenticate(req); err != nil {
+				if c.CircuitBreaker != nil {
+					c.CircuitBreaker.release()
+				}
+				return nil, errors.RequestError{
+					Message:  fmt.Sprintf("fail to authenticate %s request: %s", req.Method, err),
+					CausedBy: err}
+			}
+		}
+
+		attemptReq, end := c.Tracing.startAttempt(req, n)
+		start := time.Now()
+		res, err := c.HTTPClient.Do(attemptReq)
+		end(res, err)
+		l := attemptLog{req: req, res: res, err: err, number: n, latency: time.Since(start)}
+		if c.CircuitBreaker != nil {
+			if err != nil && req.Context().Err() != nil {
+				c.CircuitBreaker.release()
+			} else {
+				c.CircuitBreaker.record(res, err)
+			}
+		}
+		if err != nil && req.Context().Err() != nil {
+			err = handleContextError(req, err)
+			l.err = err
+			c.Logging.log(l)
+			return nil, err
+		}
+
+		a := Attempt{Number: n, Method: req.Method, URL: req.URL.String(), Err: err}
+		if res != nil {
+			a.StatusCode = res.StatusCode
+		}
+
+		if n >= attempts || (err == nil && !p.isRetryableStatus(res.StatusCode)) {
+			c.Logging.log(l)
+			p.notify(a)
+			return res, err
+		}
+
+		l.retried = true
+		c.Logging.log(l)
+		a.Delay = p.delay(n, res)
+		p.notify(a)
+
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+		if err = sleep(req.Context(), a.Delay); err != nil {
+			return nil, handleContextError(req, err)
 		}
 	}
-	return nil
+}
+
+// sleep function waits d duration or until ctx is done,
+// in the last case the ctx error is returned.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // CreateRequest function is used to create request using http verb method,
-// reqUrl and data request body using NewRequest golang object.
+// reqUrl and data request body using NewRequestWithContext golang object.
+// The ctx context is used to cancel the request or to set its deadline.
 // If the object fails to return request will return custom RequestError.
-func CreateRequest(method, reqUrl string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, reqUrl, body)
+func CreateRequest(ctx context.Context, method, reqUrl string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqUrl, body)
 	if err != nil {
 		return nil, errors.RequestError{
 			Message:  fmt.Sprintf("fail to create %s request object: %s", method, err),
@@ -96,40 +319,63 @@ func CreateRequest(method, reqUrl string, body io.Reader) (*http.Request, error)
 	return req, nil
 }
 
-// BuildPagination function return url query parameters regarding pageNum and pageSize values.
-func BuildPagination(pageNum, pageSize string) string {
+// BuildPagination method return url query parameters regarding pageNum and pageSize values.
+// If pageNum is empty no parameter is returned,
+// if pageSize is empty the client PageSize value is used.
+func (c *Client) BuildPagination(pageNum, pageSize string) url.Values {
+	v := url.Values{}
 	if len(pageNum) == 0 {
-		return ""
+		return v
 	} else if len(pageSize) == 0 {
-		return pageNumberLabel + pageNum + "&" +
-			pageSizeLabel + configs.Properties().HttpDefaultPageSize
+		pageSize = c.PageSize
 	}
 
-	return pageNumberLabel + pageNum + "&" +
-		pageSizeLabel + pageSize
+	v.Set(PageNumberParam, pageNum)
+	v.Set(PageSizeParam, pageSize)
+	return v
+}
+
+// handleContextError function returns CanceledError if err was caused by
+// the req context cancellation or deadline, otherwise err is returned.
+func handleContextError(req *http.Request, err error) error {
+	ctxErr := req.Context().Err()
+	if ctxErr == nil {
+		return err
+	}
+
+	return errors.CanceledError{
+		Message:  fmt.Sprintf("%s request to %s was canceled: %s", req.Method, req.URL, ctxErr),
+		CausedBy: ctxErr}
 }
 
 // handleExpectedStatusCode function is used to handle ErrorResponse content type coming from requests.
-// The returned type is ResponseError containing all details is needed regarding the error.
+// The returned type is ResponseError containing all details is needed regarding the error,
+// like the decoded error message, the request id, method and url.
 func handleExpectedStatusCode(res http.Response, expCode int) error {
 	if res.StatusCode != expCode {
 		var errRes ErrorResponse
 
 		errMsg := "request error with different status code, expected: %d but returned: %d with error message: %s"
 
-		var err error
-		if err = json.NewDecoder(res.Body).Decode(&errRes); err != nil {
+		resErr := errors.ResponseError{
+			StatusCode: res.StatusCode,
+			RequestID:  res.Header.Get("X-Request-Id")}
+		if res.Request != nil {
+			resErr.Method = res.Request.Method
+			resErr.URL = res.Request.URL.String()
+		}
+
+		if err := json.NewDecoder(res.Body).Decode(&errRes); err != nil {
 			decErrMsg := "fail to decode error response body with error message: %s"
 
-			return errors.ResponseError{
-				Message:    fmt.Sprintf(errMsg, expCode, res.StatusCode, fmt.Sprintf(decErrMsg, err)),
-				StatusCode: res.StatusCode,
-				CausedBy:   err}
+			resErr.Message = fmt.Sprintf(errMsg, expCode, res.StatusCode, fmt.Sprintf(decErrMsg, err))
+			resErr.CausedBy = err
+			return resErr
 		}
 
-		return errors.ResponseError{
-			Message:    fmt.Sprintf(errMsg, expCode, res.StatusCode, errRes.Message),
-			StatusCode: res.StatusCode}
+		resErr.Message = fmt.Sprintf(errMsg, expCode, res.StatusCode, errRes.Message)
+		resErr.ErrorMessage = errRes.Message
+		return resErr
 	}
 	return nil
 }
@@ -20,6 +20,10 @@ const (
 	OrganizationPath = "/organisation"
 	AccountPath      = OrganizationPath + "/accounts"
 	VersionLabel     = "version="
-	pageNumberLabel  = "page[number]="
-	pageSizeLabel    = "page[size]="
+	PageNumberParam  = "page[number]"
+	PageSizeParam    = "page[size]"
+	SortParam        = "sort"
+	FilterParam      = "filter[%s]"
+
+	IdempotencyKeyHeader = "Idempotency-Key"
 )
@@ -20,6 +20,18 @@ type ErrorResponse struct {
 }
 
 // Body type is used for response nad request http body content.
+// Links are returned only by the list responses.
 type Body struct {
-	Data interface{} `json:"data" validate:"required"`
+	Data  interface{} `json:"data" validate:"required"`
+	Links *Links      `json:"links,omitempty"`
+}
+
+// Links type is used to read list response pagination links.
+// A link is empty if the response does not contain it.
+type Links struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+	Next  string `json:"next"`
+	Prev  string `json:"prev"`
+	Self  string `json:"self"`
 }
@@ -0,0 +1,178 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DefaultLogBodySize is the default maximum number of logged body bytes.
+const DefaultLogBodySize = 1024
+
+// redactedValue replaces the redacted body field values.
+const redactedValue = "[REDACTED]"
+
+// DefaultLogRedactFields function returns the body json fields
+// that are redacted by default, the fields that identify the account holder.
+func DefaultLogRedactFields() []string {
+	return []string{
+		"account_number", "iban", "bank_id", "customer_id", "name", "alternative_names",
+		"alternative_bank_account_names", "secondary_identification",
+		"private_identification", "organisation_identification",
+	}
+}
+
+// Logging struct describes how request attempts are logged using Logger.
+// Successful attempts are logged with debug level, attempts that are retried
+// with warn level and failed attempts with error level, so the Logger handler
+// level decides which attempts are logged.
+// Request and response bodies are logged only for failed and retried attempts,
+// the RedactFields json fields are redacted and the bodies are truncated
+// to MaxBodySize bytes. If RedactFields is nil DefaultLogRedactFields are redacted,
+// an empty RedactFields slice redacts no field. If MaxBodySize is 0 DefaultLogBodySize is used,
+// if it is negative the bodies are not logged.
+type Logging struct {
+	Logger       *slog.Logger
+	MaxBodySize  int
+	RedactFields []string
+}
+
+// attemptLog struct keeps the attempt details that are logged.
+type attemptLog struct {
+	req     *http.Request
+	res     *http.Response
+	err     error
+	number  int
+	latency time.Duration
+	retried bool
+}
+
+// log method logs the a attempt.
+// If the response body is logged res.Body is replaced with a body
+// that can still be read by the caller.
+func (l *Logging) log(a attemptLog) {
+	if l == nil || l.Logger == nil {
+		return
+	}
+
+	ctx := a.req.Context()
+	level := slog.LevelDebug
+	switch {
+	case a.retried:
+		level = slog.LevelWarn
+	case a.err != nil || a.res.StatusCode >= http.StatusBadRequest:
+		level = slog.LevelError
+	}
+	if !l.Logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", a.req.Method),
+		slog.String("url", a.req.URL.String()),
+		slog.Int("attempt", a.number),
+		slog.Duration("latency", a.latency),
+	}
+	if a.res != nil {
+		attrs = append(attrs, slog.Int("status", a.res.StatusCode))
+	}
+	if a.err != nil {
+		attrs = append(attrs, slog.String("error", a.err.Error()))
+	}
+	if level != slog.LevelDebug && l.MaxBodySize >= 0 {
+		attrs = append(attrs, l.bodyAttrs(a)...)
+	}
+
+	l.Logger.LogAttrs(ctx, level, "account api request", attrs...)
+}
+
+// bodyAttrs method returns the redacted and truncated request and response bodies.
+func (l *Logging) bodyAttrs(a attemptLog) []slog.Attr {
+	attrs := []slog.Attr{}
+	if a.req.GetBody != nil {
+		if body, err := a.req.GetBody(); err == nil {
+			b, _ := io.ReadAll(body)
+			body.Close()
+			attrs = append(attrs, slog.String("request_body", l.format(b)))
+		}
+	}
+
+	if a.res != nil && a.res.Body != nil {
+		b, _ := io.ReadAll(a.res.Body)
+		a.res.Body = struct {
+			io.Reader
+			io.Closer
+		}{bytes.NewReader(b), a.res.Body}
+		attrs = append(attrs, slog.String("response_body", l.format(b)))
+	}
+	return attrs
+}
+
+// format method returns b body with the RedactFields redacted,
+// truncated to MaxBodySize bytes.
+func (l *Logging) format(b []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err == nil {
+		if r, err := json.Marshal(redact(v, l.redactFields())); err == nil {
+			b = r
+		}
+	}
+
+	if len(b) > l.maxBodySize() {
+		return string(b[:l.maxBodySize()]) + "...(truncated)"
+	}
+	return string(b)
+}
+
+// redactFields method returns RedactFields or DefaultLogRedactFields if it is nil.
+func (l *Logging) redactFields() []string {
+	if l.RedactFields == nil {
+		return DefaultLogRedactFields()
+	}
+	return l.RedactFields
+}
+
+// maxBodySize method returns MaxBodySize or DefaultLogBodySize if it is not set.
+func (l *Logging) maxBodySize() int {
+	if l.MaxBodySize == 0 {
+		return DefaultLogBodySize
+	}
+	return l.MaxBodySize
+}
+
+// redact function replaces the values of the fields from v json value.
+func redact(v interface{}, fields []string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range t {
+			t[k] = redact(fv, fields)
+			for _, f := range fields {
+				if k == f {
+					t[k] = redactedValue
+				}
+			}
+		}
+	case []interface{}:
+		for i, iv := range t {
+			t[i] = redact(iv, fields)
+		}
+	}
+	return v
+}
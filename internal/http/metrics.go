@@ -0,0 +1,39 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import "time"
+
+// MetricsCollector interface is used to record metrics about the requests
+// sent by the client, like request count, latency and error count.
+// ObserveRequest is called once for every sent request, after all its attempts.
+type MetricsCollector interface {
+	ObserveRequest(o RequestObservation)
+}
+
+// RequestObservation struct describes a finished request.
+// Operation is the request operation name, like "account.create".
+// StatusCode is 0 if no response was received and Err is the error
+// returned to the caller, or nil if the request succeeded.
+// CacheHit is true if the response was read from the client Cache
+// and no request was sent.
+type RequestObservation struct {
+	Operation  string
+	Method     string
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+	CacheHit   bool
+}
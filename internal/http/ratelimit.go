@@ -0,0 +1,161 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter struct is a token bucket rate limiter shared by all goroutines
+// that send requests using the same client.
+// Every request takes a token from the client bucket and, if the request
+// operation has its own budget, a token from the operation bucket.
+// The buckets are refilled with Rate tokens per second up to Burst tokens.
+type RateLimiter struct {
+	mu         sync.Mutex
+	bucket     *bucket
+	operations map[string]*bucket
+}
+
+// RateLimiterState struct describes the current state of a RateLimiter.
+// Operations contains the state of every operation budget.
+type RateLimiterState struct {
+	BucketState
+	Operations map[string]BucketState
+}
+
+// BucketState struct describes a token bucket, Tokens is the number of
+// tokens available now, it is negative if requests are waiting for tokens.
+type BucketState struct {
+	Rate   float64
+	Burst  int
+	Tokens float64
+}
+
+// bucket struct keeps a token bucket state.
+type bucket struct {
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter function returns a RateLimiter allowing rate requests per second
+// with bursts of at most burst requests. The bucket starts full.
+// If burst is less than 1 it is set to 1, if rate is 0 or less
+// the requests are not limited.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		bucket:     newBucket(rate, burst),
+		operations: map[string]*bucket{},
+	}
+}
+
+// SetOperationLimit method sets the op operation budget, like "account.create",
+// to rate requests per second with bursts of at most burst requests.
+// The requests of op operation are limited by both the operation budget
+// and the rate limiter limit.
+func (l *RateLimiter) SetOperationLimit(op string, rate float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.operations[op] = newBucket(rate, burst)
+}
+
+// Wait method waits until a request of op operation is allowed to be sent.
+// If ctx is done before the request is allowed the ctx error is returned
+// and the reserved tokens are given back.
+func (l *RateLimiter) Wait(ctx context.Context, op string) error {
+	l.mu.Lock()
+	now := time.Now()
+	buckets := []*bucket{l.bucket}
+	if b, ok := l.operations[op]; ok {
+		buckets = append(buckets, b)
+	}
+
+	var d time.Duration
+	for _, b := range buckets {
+		if bd := b.reserve(now); bd > d {
+			d = bd
+		}
+	}
+	l.mu.Unlock()
+
+	if d == 0 {
+		return nil
+	}
+	if err := sleep(ctx, d); err != nil {
+		l.mu.Lock()
+		for _, b := range buckets {
+			if b.rate > 0 {
+				b.tokens = math.Min(b.tokens+1, float64(b.burst))
+			}
+		}
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// State method returns the current rate limiter state.
+func (l *RateLimiter) State() RateLimiterState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	s := RateLimiterState{BucketState: l.bucket.state(now), Operations: map[string]BucketState{}}
+	for op, b := range l.operations {
+		s.Operations[op] = b.state(now)
+	}
+	return s
+}
+
+// newBucket function returns a full bucket.
+func newBucket(rate float64, burst int) *bucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &bucket{rate: rate, burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+// refill method adds the tokens produced since the last refill.
+func (b *bucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = math.Min(b.tokens+now.Sub(b.last).Seconds()*b.rate, float64(b.burst))
+		b.last = now
+	}
+}
+
+// reserve method takes a token and returns the time to wait until the token is available.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// state method returns the bucket state.
+func (b *bucket) state(now time.Time) BucketState {
+	b.refill(now)
+	return BucketState{Rate: b.rate, Burst: b.burst, Tokens: b.tokens}
+}
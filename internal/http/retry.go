@@ -0,0 +1,170 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy struct describes how failed requests are retried.
+// MaxAttempts is the maximum number of attempts including the first one,
+// the delay between attempts grows exponentially starting with BaseDelay
+// and it is never bigger than MaxDelay, if MaxDelay is 0 the delay is not limited.
+// Jitter is the fraction (between 0 and 1) of the delay that is randomly removed,
+// it is used to avoid many clients retrying at the same time.
+// Only responses with RetryableStatus codes and connection errors are retried,
+// and only for idempotent http methods or requests with an IdempotencyKeyHeader
+// unless RetryNonIdempotent is true.
+// OnAttempt, if it is not nil, is called after every attempt.
+type RetryPolicy struct {
+	MaxAttempts        int
+	BaseDelay          time.Duration
+	MaxDelay           time.Duration
+	Jitter             float64
+	RetryableStatus    []int
+	RetryNonIdempotent bool
+	OnAttempt          func(a Attempt)
+}
+
+// Attempt struct describes a finished request attempt.
+// StatusCode is 0 if the attempt failed with Err before a response was received.
+// Delay is the time waited before the next attempt, or 0 if there is no next attempt.
+type Attempt struct {
+	Number     int
+	Method     string
+	URL        string
+	StatusCode int
+	Err        error
+	Delay      time.Duration
+}
+
+// DefaultRetryPolicy function returns a RetryPolicy with 3 attempts,
+// exponential delay between 100 milliseconds and 5 seconds, 20% jitter,
+// retrying 429, 502, 503 and 504 status codes.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.2,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// attempts method returns the maximum number of attempts for req,
+// requests with an IdempotencyKeyHeader are retried like idempotent requests.
+func (p *RetryPolicy) attempts(req *http.Request) int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	idempotent := isIdempotent(req.Method) || len(req.Header.Get(IdempotencyKeyHeader)) != 0
+	if !p.RetryNonIdempotent && !idempotent {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// isRetryableStatus method checks if code is one of the RetryableStatus codes.
+func (p *RetryPolicy) isRetryableStatus(code int) bool {
+	for _, rc := range p.RetryableStatus {
+		if rc == code {
+			return true
+		}
+	}
+	return false
+}
+
+// delay method returns the time to wait after the attempt number n.
+// If res contains a valid Retry-After header its value is used instead
+// of the exponential delay, in both cases the delay is limited by MaxDelay.
+func (p *RetryPolicy) delay(n int, res *http.Response) time.Duration {
+	if d, ok := retryAfter(res); ok {
+		return p.limit(d)
+	}
+
+	d := p.BaseDelay
+	for i := 1; i < n && (p.MaxDelay == 0 || d < p.MaxDelay); i++ {
+		if d > math.MaxInt64/2 {
+			d = math.MaxInt64
+			break
+		}
+		d *= 2
+	}
+	d = p.limit(d)
+
+	if p.Jitter > 0 && d > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// limit method returns d or MaxDelay if d is bigger than MaxDelay.
+func (p *RetryPolicy) limit(d time.Duration) time.Duration {
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return d
+}
+
+// notify method calls OnAttempt hook if it is set.
+func (p *RetryPolicy) notify(a Attempt) {
+	if p != nil && p.OnAttempt != nil {
+		p.OnAttempt(a)
+	}
+}
+
+// retryAfter function returns the delay from res Retry-After header,
+// the header value can be given in seconds or as http date.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+
+	v := res.Header.Get("Retry-After")
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// isIdempotent function checks if method http verb is idempotent.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
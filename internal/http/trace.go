@@ -0,0 +1,133 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"strings"
+)
+
+// TracerName is the instrumentation name of the client tracer.
+const TracerName = "github.com/pancudaniel7/fake-api-client"
+
+// trace span attribute keys.
+const (
+	methodAttr     = attribute.Key("http.request.method")
+	urlAttr        = attribute.Key("url.full")
+	statusAttr     = attribute.Key("http.response.status_code")
+	attemptAttr    = attribute.Key("http.request.resend_count")
+	retryCountAttr = attribute.Key("fake_api_client.retry_count")
+	operationAttr  = attribute.Key("fake_api_client.operation")
+	cacheHitAttr   = attribute.Key("fake_api_client.cache_hit")
+)
+
+// Tracing struct describes how requests are traced using OpenTelemetry.
+// Every request has an operation span, like "Account.Create", with a child span
+// for every attempt. The operation span is named after the request operation,
+// not after the service method, so ListBy requests have an "Account.Fetch" span. The attempt span context is injected in the request headers
+// using Propagator, if Propagator is nil the W3C traceparent propagator is used.
+type Tracing struct {
+	Tracer     trace.Tracer
+	Propagator propagation.TextMapPropagator
+}
+
+// startOperation method starts the operation span of req and returns
+// req using the span context. The returned function ends the span
+// recording the response status code and the request error.
+func (t *Tracing) startOperation(req *http.Request) (*http.Request, func(status int, err error)) {
+	if t == nil || t.Tracer == nil {
+		return req, func(int, error) {}
+	}
+
+	op := Operation(req.Context())
+	ctx, span := t.Tracer.Start(req.Context(), operationSpanName(op),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(operationAttr.String(op), methodAttr.String(req.Method), urlAttr.String(req.URL.String())))
+
+	return req.WithContext(ctx), func(status int, err error) {
+		endSpan(span, status, err)
+	}
+}
+
+// startAttempt method starts the n attempt span of req, injects the span context
+// in req headers and returns req using the span context. The returned function
+// ends the span recording the attempt response status code and error.
+func (t *Tracing) startAttempt(req *http.Request, n int) (*http.Request, func(res *http.Response, err error)) {
+	if t == nil || t.Tracer == nil {
+		return req, func(*http.Response, error) {}
+	}
+
+	trace.SpanFromContext(req.Context()).SetAttributes(retryCountAttr.Int(n - 1))
+
+	ctx, span := t.Tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(methodAttr.String(req.Method), urlAttr.String(req.URL.String()), attemptAttr.Int(n-1)))
+	t.propagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return req.WithContext(ctx), func(res *http.Response, err error) {
+		status := 0
+		if res != nil {
+			status = res.StatusCode
+		}
+		endSpan(span, status, err)
+	}
+}
+
+// markCacheHit method records on the req operation span that the response
+// was read from the client Cache, so the span has no attempt spans.
+func (t *Tracing) markCacheHit(req *http.Request) {
+	if t == nil || t.Tracer == nil {
+		return
+	}
+	trace.SpanFromContext(req.Context()).SetAttributes(cacheHitAttr.Bool(true))
+}
+
+// propagator method returns Propagator or the W3C trace context propagator.
+func (t *Tracing) propagator() propagation.TextMapPropagator {
+	if t.Propagator == nil {
+		return propagation.TraceContext{}
+	}
+	return t.Propagator
+}
+
+// endSpan function records status and err on span and ends it.
+func endSpan(span trace.Span, status int, err error) {
+	if status != 0 {
+		span.SetAttributes(statusAttr.Int(status))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if status >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	span.End()
+}
+
+// operationSpanName function returns the span name of op operation,
+// like "Account.Create" for "account.create".
+func operationSpanName(op string) string {
+	parts := strings.Split(op, ".")
+	for i, p := range parts {
+		if len(p) != 0 {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, ".")
+}
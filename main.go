@@ -14,10 +14,13 @@
 
 package main
 
-import "fmt"
+import (
+	"github.com/pancudaniel7/fake-api-client/internal/cli"
+	"os"
+)
 
 var Version = "No Version Provided"
 
 func main() {
-	fmt.Printf("App Version: %s\n", Version)
+	os.Exit(cli.Run(Version, os.Args[1:], os.Stdout, os.Stderr))
 }
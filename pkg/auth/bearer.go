@@ -0,0 +1,31 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth provides the authenticators used to add credentials
+// to the client requests.
+package auth
+
+import "net/http"
+
+// Bearer struct is an authenticator that adds the Token
+// as bearer token to the Authorization header.
+type Bearer struct {
+	Token string
+}
+
+// Authenticate method sets the request Authorization header.
+func (b Bearer) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+b.Token)
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// signed headers, (request-target) is the lower case request method
+// followed by the request path and query.
+const (
+	requestTarget = "(request-target)"
+	signedHeaders = requestTarget + " host date digest"
+)
+
+// HTTPSignature struct is an authenticator that signs the requests using
+// HTTP Signatures (draft-cavage-http-signatures).
+// The (request-target), Host, Date and Digest headers are signed with Key,
+// which should be an *rsa.PrivateKey or ed25519.PrivateKey.
+// The signature is added to the Authorization header together with KeyID.
+type HTTPSignature struct {
+	KeyID string
+	Key   crypto.Signer
+	Now   func() time.Time
+}
+
+// NewHTTPSignature function returns an HTTPSignature authenticator
+// that signs the requests with keyID and key.
+func NewHTTPSignature(keyID string, key crypto.Signer) *HTTPSignature {
+	return &HTTPSignature{KeyID: keyID, Key: key, Now: time.Now}
+}
+
+// Authenticate method sets the request Date, Digest and Authorization headers.
+func (s *HTTPSignature) Authenticate(req *http.Request) error {
+	alg, err := algorithm(s.Key.Public())
+	if err != nil {
+		return err
+	}
+
+	body, err := requestBody(req)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	req.Header.Set("Date", now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Digest", digest(body))
+
+	msg := signingString(req, signedHeaders)
+
+	var sig []byte
+	if alg == "ed25519" {
+		sig, err = s.Key.Sign(rand.Reader, msg, crypto.Hash(0))
+	} else {
+		h := sha256.Sum256(msg)
+		sig, err = s.Key.Sign(rand.Reader, h[:], crypto.SHA256)
+	}
+	if err != nil {
+		return fmt.Errorf("fail to sign request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(`Signature keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		s.KeyID, alg, signedHeaders, base64.StdEncoding.EncodeToString(sig)))
+	return nil
+}
+
+// DefaultMaxClockSkew is the usual maximum difference between the signed
+// request Date and the verifier clock.
+const DefaultMaxClockSkew = 5 * time.Minute
+
+// VerifyHTTPSignature function verifies the req HTTP Signature created by HTTPSignature
+// using the pub public key. The signed headers list has to contain (request-target),
+// host, date and, if the request has a body, digest. The Date header can differ from
+// the current time by at most maxSkew and the Digest header has to match the body.
+// The function returns the signature keyId if the request is valid.
+// VerifyHTTPSignature can be used by servers or tests to check signed requests.
+func VerifyHTTPSignature(req *http.Request, pub crypto.PublicKey, maxSkew time.Duration) (string, error) {
+	params, err := parseSignature(req.Header.Get("Authorization"))
+	if err != nil {
+		return "", err
+	}
+
+	alg, err := algorithm(pub)
+	if err != nil {
+		return "", err
+	}
+	if params["algorithm"] != alg {
+		return "", fmt.Errorf("unexpected signature algorithm: %s", params["algorithm"])
+	}
+
+	body, err := requestBody(req)
+	if err != nil {
+		return "", err
+	}
+
+	required := []string{requestTarget, "host", "date"}
+	if len(body) != 0 {
+		required = append(required, "digest")
+	}
+	signed := strings.Fields(strings.ToLower(params["headers"]))
+	for _, h := range required {
+		if !slices.Contains(signed, h) {
+			return "", fmt.Errorf("%s header is not signed", h)
+		}
+	}
+
+	date, err := http.ParseTime(req.Header.Get("Date"))
+	if err != nil {
+		return "", fmt.Errorf("invalid date header: %w", err)
+	}
+	if skew := time.Since(date); skew > maxSkew || skew < -maxSkew {
+		return "", fmt.Errorf("request date %s differs from the current time more than %s",
+			req.Header.Get("Date"), maxSkew)
+	}
+
+	if len(body) != 0 || len(req.Header.Get("Digest")) != 0 {
+		if req.Header.Get("Digest") != digest(body) {
+			return "", errors.New("request digest does not match the body")
+		}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return "", fmt.Errorf("fail to decode signature: %w", err)
+	}
+
+	msg := signingString(req, strings.Join(signed, " "))
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, msg, sig) {
+			return "", errors.New("invalid signature")
+		}
+	case *rsa.PublicKey:
+		h := sha256.Sum256(msg)
+		if err = rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], sig); err != nil {
+			return "", fmt.Errorf("invalid signature: %w", err)
+		}
+	}
+	return params["keyId"], nil
+}
+
+// algorithm function returns the signature algorithm name of the pub key type.
+func algorithm(pub crypto.PublicKey) (string, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		return "rsa-sha256", nil
+	case ed25519.PublicKey:
+		return "ed25519", nil
+	}
+	return "", fmt.Errorf("unsupported key type: %T", pub)
+}
+
+// signingString function returns the string signed for the req headers list.
+func signingString(req *http.Request, headers string) []byte {
+	var lines []string
+	for _, h := range strings.Fields(headers) {
+		switch h {
+		case requestTarget:
+			lines = append(lines, fmt.Sprintf("%s: %s %s", h, strings.ToLower(req.Method), req.URL.RequestURI()))
+		case "host":
+			host := req.Host
+			if len(host) == 0 {
+				host = req.URL.Host
+			}
+			lines = append(lines, "host: "+host)
+		default:
+			lines = append(lines, fmt.Sprintf("%s: %s", h, req.Header.Get(h)))
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// digest function returns the Digest header value of body.
+func digest(body []byte) string {
+	h := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(h[:])
+}
+
+// requestBody function returns the req body without consuming it.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	rc := req.Body
+	if req.GetBody != nil {
+		var err error
+		if rc, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	b, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("fail to read request body: %w", err)
+	}
+	if req.GetBody == nil {
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+	return b, nil
+}
+
+// parseSignature function returns the params of the Signature authorization value.
+func parseSignature(v string) (map[string]string, error) {
+	if !strings.HasPrefix(v, "Signature ") {
+		return nil, errors.New("authorization header is not a signature")
+	}
+
+	params := map[string]string{}
+	for _, p := range strings.Split(strings.TrimPrefix(v, "Signature "), ",") {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid signature param: %s", p)
+		}
+		params[strings.TrimSpace(kv[0])] = strings.Trim(kv[1], `"`)
+	}
+	return params, nil
+}
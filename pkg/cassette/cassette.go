@@ -0,0 +1,310 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cassette provides an http transport that records request and response
+// pairs to cassette files and replays them, so the client can be tested
+// deterministically without the account api.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode type describes how Transport handles the requests.
+type Mode int
+
+// Transport modes, ModeReplay returns only recorded responses,
+// ModeRecord sends the requests and records them
+// and ModePassthrough only sends the requests.
+const (
+	ModeReplay Mode = iota
+	ModeRecord
+	ModePassthrough
+)
+
+// RedactedValue is the value saved instead of the redacted header values.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedHeaders are the headers redacted by every Transport.
+var DefaultRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Signature", "X-Api-Key"}
+
+// Request struct defines a recorded request.
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// Response struct defines a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Interaction struct defines a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Transport struct is an http.RoundTripper that records or replays
+// the requests regarding its Mode, using the cassette file found at Path.
+// Next is the transport used to send the requests in record and passthrough modes,
+// if it is nil http.DefaultTransport is used.
+// RedactHeaders values are replaced with RedactedValue before they are recorded,
+// if it is nil DefaultRedactedHeaders are redacted.
+// A Transport created without New, like Transport{}, starts with no interactions.
+type Transport struct {
+	Path          string
+	Mode          Mode
+	Next          http.RoundTripper
+	RedactHeaders []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New function returns a Transport using the cassette file from path.
+// In replay mode the cassette file is loaded and an error is returned
+// if it cannot be read. If next is nil http.DefaultTransport is used.
+func New(path string, mode Mode, next http.RoundTripper) (*Transport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t := &Transport{Path: path, Mode: mode, Next: next, RedactHeaders: DefaultRedactedHeaders}
+	if mode != ModeReplay {
+		return t, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read cassette %s: %w", path, err)
+	}
+	if err = json.Unmarshal(b, &t.interactions); err != nil {
+		return nil, fmt.Errorf("fail to decode cassette %s: %w", path, err)
+	}
+	t.used = make([]bool, len(t.interactions))
+	return t, nil
+}
+
+// ParseMode function returns the Mode named by s: "record", "replay" or "passthrough".
+// If s is empty ModeReplay is returned.
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(s) {
+	case "", "replay":
+		return ModeReplay, nil
+	case "record":
+		return ModeRecord, nil
+	case "passthrough":
+		return ModePassthrough, nil
+	}
+	return ModeReplay, fmt.Errorf("unknown cassette mode: %s", s)
+}
+
+// RoundTrip method handles req regarding the transport Mode, req is not modified.
+// In replay mode the first not used interaction matching req method, path,
+// query and body is returned, if there is no such interaction an error is returned.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, req, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	rec := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Header: t.redact(req.Header),
+		Body:   body,
+	}
+
+	switch t.Mode {
+	case ModeReplay:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return t.replay(req, rec)
+	case ModeRecord:
+		return t.record(req, rec)
+	}
+	return t.next().RoundTrip(req)
+}
+
+// Save method writes the recorded interactions to the cassette file,
+// the cassette directory is created if it does not exist.
+// Save does nothing if the transport is not in record mode.
+func (t *Transport) Save() error {
+	if t.Mode != ModeRecord {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, err := json.MarshalIndent(t.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(t.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.Path, b, 0644)
+}
+
+// record method sends req and records its response.
+func (t *Transport) record(req *http.Request, rec Request) (*http.Response, error) {
+	res, err := t.next().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readBody(&res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.interactions = append(t.interactions, Interaction{
+		Request:  rec,
+		Response: Response{StatusCode: res.StatusCode, Header: t.redact(res.Header), Body: body},
+	})
+	t.mu.Unlock()
+	return res, nil
+}
+
+// replay method returns the recorded response of the first not used interaction matching rec.
+func (t *Transport) replay(req *http.Request, rec Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.used) < len(t.interactions) {
+		t.used = append(t.used, make([]bool, len(t.interactions)-len(t.used))...)
+	}
+	for i, in := range t.interactions {
+		if t.used[i] || !matches(in.Request, rec) {
+			continue
+		}
+
+		t.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette %s has no interaction for %s %s?%s", t.Path, rec.Method, rec.Path, rec.Query)
+}
+
+// next method returns the Next transport or http.DefaultTransport if it is nil.
+func (t *Transport) next() http.RoundTripper {
+	if t.Next == nil {
+		return http.DefaultTransport
+	}
+	return t.Next
+}
+
+// redact method returns a copy of h with the RedactHeaders values replaced,
+// or the DefaultRedactedHeaders values if RedactHeaders is nil.
+func (t *Transport) redact(h http.Header) http.Header {
+	names := t.RedactHeaders
+	if names == nil {
+		names = DefaultRedactedHeaders
+	}
+
+	c := h.Clone()
+	for _, name := range names {
+		if len(c.Values(name)) != 0 {
+			c.Set(name, RedactedValue)
+		}
+	}
+	return c
+}
+
+// matches function checks if the recorded request r matches the request rec,
+// JSON bodies are compared ignoring their formatting.
+func matches(r, rec Request) bool {
+	return r.Method == rec.Method && r.Path == rec.Path &&
+		r.Query == rec.Query && normalize(r.Body) == normalize(rec.Body)
+}
+
+// normalize function returns the compact JSON form of body,
+// or body if it is not a valid JSON.
+func normalize(body string) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(body)); err != nil {
+		return body
+	}
+	return buf.String()
+}
+
+// requestBody function returns the req body and the request that should be sent instead of req.
+// The body is read using req.GetBody, or if it is not set from a copy of req,
+// so req is never modified.
+func requestBody(req *http.Request) (string, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", req, nil
+	}
+
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return "", nil, fmt.Errorf("fail to read body: %w", err)
+		}
+		body, err := readBody(&rc)
+		return body, req, err
+	}
+
+	c := req.Clone(req.Context())
+	body, err := readBody(&c.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	c.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(body)), nil
+	}
+	return body, c, nil
+}
+
+// readBody function reads and replaces the body rc, so it can be read again.
+func readBody(rc *io.ReadCloser) (string, error) {
+	if *rc == nil || *rc == http.NoBody {
+		return "", nil
+	}
+
+	b, err := io.ReadAll(*rc)
+	(*rc).Close()
+	if err != nil {
+		return "", fmt.Errorf("fail to read body: %w", err)
+	}
+	*rc = io.NopCloser(bytes.NewReader(b))
+	return string(b), nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import _http "github.com/pancudaniel7/fake-api-client/internal/http"
+
+// CircuitBreaker type is used to fail fast when the server is down,
+// it is used with WithCircuitBreaker option.
+type CircuitBreaker = _http.CircuitBreaker
+
+// CircuitBreakerSettings type describes when a CircuitBreaker changes its state.
+type CircuitBreakerSettings = _http.CircuitBreakerSettings
+
+// CircuitState type describes the state of a CircuitBreaker.
+type CircuitState = _http.CircuitState
+
+// circuit breaker states.
+const (
+	CircuitClosed   = _http.CircuitClosed
+	CircuitOpen     = _http.CircuitOpen
+	CircuitHalfOpen = _http.CircuitHalfOpen
+)
+
+// NewCircuitBreaker function returns a closed CircuitBreaker using s settings.
+func NewCircuitBreaker(s CircuitBreakerSettings) *CircuitBreaker {
+	return _http.NewCircuitBreaker(s)
+}
+
+// DefaultCircuitBreakerSettings function returns CircuitBreakerSettings that open
+// the circuit after 5 consecutive failures or when half of at least 10 requests
+// from the last minute failed, with 30 seconds cool-down and 1 trial request.
+func DefaultCircuitBreakerSettings() CircuitBreakerSettings {
+	return _http.DefaultCircuitBreakerSettings()
+}
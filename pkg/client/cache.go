@@ -0,0 +1,34 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	_http "github.com/pancudaniel7/fake-api-client/internal/http"
+	"time"
+)
+
+// Cache type is a read-through cache of fetched resources,
+// it is used with WithCache option.
+type Cache = _http.Cache
+
+// CacheStats type describes the Cache usage.
+type CacheStats = _http.CacheStats
+
+// NewCache function returns a Cache keeping the fetched resources fresh for ttl,
+// stale resources are revalidated using their ETag when the server supports it.
+// At most maxEntries resources are kept, if maxEntries is 0 or less the number is not limited.
+func NewCache(ttl time.Duration, maxEntries int) *Cache {
+	return _http.NewCache(ttl, maxEntries)
+}
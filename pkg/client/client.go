@@ -0,0 +1,54 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	_http "github.com/pancudaniel7/fake-api-client/internal/http"
+	"sync"
+)
+
+// Client struct is used to communicate with the fake-api service.
+// Every Client keeps its own configuration so more clients,
+// pointing to different environments, can be used in the same process.
+type Client struct {
+	*_http.Client
+}
+
+var (
+	once sync.Once
+	c    *Client
+)
+
+// New function returns a new Client object.
+// The client is initialised with the library properties values
+// (environment variables or defaults) and after that every opts
+// option is applied in the given order.
+func New(opts ...Option) *Client {
+	nc := &Client{Client: _http.NewClient()}
+	for _, opt := range opts {
+		opt(nc)
+	}
+	return nc
+}
+
+// Default function returns the Client singleton object
+// initialised only with the library properties values.
+// Default is used by services that were created without a client.
+func Default() *Client {
+	once.Do(func() {
+		c = &Client{Client: _http.APIClient()}
+	})
+	return c
+}
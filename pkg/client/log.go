@@ -0,0 +1,27 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import _http "github.com/pancudaniel7/fake-api-client/internal/http"
+
+// Logging type describes how request attempts are logged,
+// it is used with WithLogging option.
+type Logging = _http.Logging
+
+// DefaultLogRedactFields function returns the body json fields
+// that identify the account holder and are redacted by default.
+func DefaultLogRedactFields() []string {
+	return _http.DefaultLogRedactFields()
+}
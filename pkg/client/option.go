@@ -0,0 +1,149 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	_http "github.com/pancudaniel7/fake-api-client/internal/http"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Option type is used to customise a Client object when it is created by New.
+type Option func(c *Client)
+
+// WithBaseURL option sets the base api url used for every request.
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		c.BaseURL = url
+	}
+}
+
+// WithTimeout option sets the http client request time out.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.HTTPClient.Timeout = d
+	}
+}
+
+// WithTransport option sets the http client transport,
+// the transport is used to make every http request.
+func WithTransport(t http.RoundTripper) Option {
+	return func(c *Client) {
+		c.HTTPClient.Transport = t
+	}
+}
+
+// WithHTTPClient option replaces the http client used to make requests.
+// Options applied after WithHTTPClient, like WithTimeout or WithTransport,
+// will change the given hc object.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithPageSize option sets the default page size used when resources are listed.
+func WithPageSize(size int) Option {
+	return func(c *Client) {
+		c.PageSize = strconv.Itoa(size)
+	}
+}
+
+// WithRecordVersion option sets the default record version used when resources are deleted.
+func WithRecordVersion(version int) Option {
+	return func(c *Client) {
+		c.RecordVersion = strconv.Itoa(version)
+	}
+}
+
+// WithRetryPolicy option sets the policy used to retry failed requests.
+// By default every request is sent only once.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.RetryPolicy = &p
+	}
+}
+
+// WithAuthenticator option sets the authenticator used to add
+// credentials to every request, like auth.Bearer or auth.HTTPSignature.
+func WithAuthenticator(a Authenticator) Option {
+	return func(c *Client) {
+		c.Authenticator = a
+	}
+}
+
+// WithLogger option logs every request attempt using l,
+// the account holder body fields are redacted using DefaultLogRedactFields.
+// Successful attempts are logged with debug level, so a logger
+// with error level logs only the failed requests.
+func WithLogger(l *slog.Logger) Option {
+	return WithLogging(Logging{Logger: l, RedactFields: DefaultLogRedactFields()})
+}
+
+// WithLogging option sets how every request attempt is logged,
+// if l.RedactFields is nil the DefaultLogRedactFields are redacted.
+func WithLogging(l Logging) Option {
+	return func(c *Client) {
+		c.Logging = &l
+	}
+}
+
+// WithMetrics option sets the collector that observes every request,
+// like metrics.Prometheus.
+func WithMetrics(m MetricsCollector) Option {
+	return func(c *Client) {
+		c.Metrics = m
+	}
+}
+
+// WithRateLimiter option sets the rate limiter that every request attempt waits for.
+// The same rate limiter can be shared by more clients.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) {
+		c.RateLimiter = l
+	}
+}
+
+// WithCache option sets the cache used by the resource fetch requests, like Account.ListBy.
+// The cached resources are removed when they are deleted or updated using the client.
+func WithCache(cache *Cache) Option {
+	return func(c *Client) {
+		c.Cache = cache
+	}
+}
+
+// WithCircuitBreaker option sets the circuit breaker used to fail fast,
+// with errors.CircuitOpenError, while the server is down.
+func WithCircuitBreaker(b *CircuitBreaker) Option {
+	return func(c *Client) {
+		c.CircuitBreaker = b
+	}
+}
+
+// WithTracerProvider option traces every request using a tracer from tp,
+// the trace context is propagated using W3C traceparent header.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return WithTracing(Tracing{Tracer: tp.Tracer(_http.TracerName)})
+}
+
+// WithTracing option sets how every request is traced.
+func WithTracing(t Tracing) Option {
+	return func(c *Client) {
+		c.Tracing = &t
+	}
+}
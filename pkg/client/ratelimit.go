@@ -0,0 +1,34 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import _http "github.com/pancudaniel7/fake-api-client/internal/http"
+
+// RateLimiter type is a token bucket rate limiter,
+// it is used with WithRateLimiter option.
+type RateLimiter = _http.RateLimiter
+
+// RateLimiterState type describes the current state of a RateLimiter.
+type RateLimiterState = _http.RateLimiterState
+
+// BucketState type describes a RateLimiter token bucket.
+type BucketState = _http.BucketState
+
+// NewRateLimiter function returns a RateLimiter allowing rate requests per second
+// with bursts of at most burst requests.
+// Operation budgets can be added using SetOperationLimit.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return _http.NewRateLimiter(rate, burst)
+}
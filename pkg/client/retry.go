@@ -0,0 +1,32 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import _http "github.com/pancudaniel7/fake-api-client/internal/http"
+
+// RetryPolicy type describes how failed requests are retried,
+// it is used with WithRetryPolicy option.
+type RetryPolicy = _http.RetryPolicy
+
+// Attempt type describes a finished request attempt,
+// it is received by the RetryPolicy OnAttempt hook.
+type Attempt = _http.Attempt
+
+// DefaultRetryPolicy function returns a RetryPolicy with 3 attempts,
+// exponential delay between 100 milliseconds and 5 seconds, 20% jitter,
+// retrying 429, 502, 503 and 504 status codes of idempotent requests.
+func DefaultRetryPolicy() RetryPolicy {
+	return _http.DefaultRetryPolicy()
+}
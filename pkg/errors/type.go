@@ -14,13 +14,36 @@
 
 package errors
 
-import "fmt"
+import (
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sentinel errors used to check the kind of ResponseError using errors.Is.
+var (
+	ErrNotFound            = goerrors.New("resource not found")
+	ErrConflict            = goerrors.New("resource conflict")
+	ErrRateLimited         = goerrors.New("rate limited")
+	ErrUnauthorized        = goerrors.New("unauthorized")
+	ErrServer              = goerrors.New("server error")
+	ErrCircuitOpen         = goerrors.New("circuit breaker is open")
+	ErrConflictingResource = goerrors.New("conflicting resource")
+)
 
 // ResponseError struct defines a fail response.
+// ErrorMessage is the decoded response error_message, RequestID is the
+// response X-Request-Id header value, Method and URL describe the request.
 type ResponseError struct {
-	StatusCode int
-	Message    string
-	CausedBy   error
+	StatusCode   int
+	Message      string
+	ErrorMessage string
+	RequestID    string
+	Method       string
+	URL          string
+	CausedBy     error
 }
 
 // Error returns error string response for ResponseError.
@@ -28,7 +51,31 @@ func (e ResponseError) Error() string {
 	return fmt.Sprintf("%s, status code: %d caused by: %s", e.Message, e.StatusCode, e.CausedBy)
 }
 
-// ResponseError struct defines a fail request.
+// Unwrap returns the error that caused ResponseError.
+func (e ResponseError) Unwrap() error {
+	return e.CausedBy
+}
+
+// Is method reports if ResponseError status code matches the target sentinel error:
+// 404 is ErrNotFound, 409 is ErrConflict, 429 is ErrRateLimited,
+// 401 and 403 are ErrUnauthorized and every 5xx is ErrServer.
+func (e ResponseError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// RequestError struct defines a fail request.
 type RequestError struct {
 	Message  string
 	CausedBy error
@@ -38,3 +85,146 @@ type RequestError struct {
 func (e RequestError) Error() string {
 	return fmt.Sprintf("%s, caused by: %s", e.Message, e.CausedBy)
 }
+
+// Unwrap returns the error that caused RequestError.
+func (e RequestError) Unwrap() error {
+	return e.CausedBy
+}
+
+// CanceledError struct defines a request canceled by its context,
+// CausedBy is context.Canceled or context.DeadlineExceeded.
+type CanceledError struct {
+	Message  string
+	CausedBy error
+}
+
+// Error returns error string response for CanceledError.
+func (e CanceledError) Error() string {
+	return fmt.Sprintf("%s, caused by: %s", e.Message, e.CausedBy)
+}
+
+// Unwrap returns the context error that caused CanceledError.
+func (e CanceledError) Unwrap() error {
+	return e.CausedBy
+}
+
+// FieldError struct defines a field that failed the validation.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Error returns error string response for FieldError.
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// ValidationError struct defines a resource that failed the client validation,
+// Fields contains every field that is not valid.
+type ValidationError struct {
+	Message string
+	Fields  []FieldError
+}
+
+// Error returns error string response for ValidationError.
+func (e ValidationError) Error() string {
+	msg := e.Message + ", validation failure list:"
+	for _, f := range e.Fields {
+		msg += "\n" + f.Error()
+	}
+	return msg
+}
+
+// VersionConflictError struct defines a resource update that failed
+// because Version is not the resource current version.
+type VersionConflictError struct {
+	ID       string
+	Version  int
+	CausedBy error
+}
+
+// Error returns error string response for VersionConflictError.
+func (e VersionConflictError) Error() string {
+	return fmt.Sprintf("resource %s version %d is not the current version, caused by: %s", e.ID, e.Version, e.CausedBy)
+}
+
+// Unwrap returns the response error that caused VersionConflictError.
+func (e VersionConflictError) Unwrap() error {
+	return e.CausedBy
+}
+
+// PanicError struct defines a panic recovered from an asynchronous function,
+// Value is the recovered panic value and Stack is the goroutine stack trace.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error returns error string response for PanicError.
+func (e PanicError) Error() string {
+	return fmt.Sprintf("recovered panic: %v", e.Value)
+}
+
+// AggregateError struct defines more errors returned together.
+type AggregateError struct {
+	Message string
+	Errors  []error
+}
+
+// Error returns error string response for AggregateError.
+func (e AggregateError) Error() string {
+	msg := e.Message + ", errors:"
+	for _, err := range e.Errors {
+		msg += "\n" + err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the aggregated errors.
+func (e AggregateError) Unwrap() []error {
+	return e.Errors
+}
+
+// CircuitOpenError struct defines a request that was not sent because
+// the client circuit breaker is open, RetryAfter is the time left until
+// the circuit breaker allows trial requests.
+// CircuitOpenError matches ErrCircuitOpen using errors.Is.
+type CircuitOpenError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+// Error returns error string response for CircuitOpenError.
+func (e CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s, retry after: %s", e.Message, e.RetryAfter)
+}
+
+// Unwrap returns ErrCircuitOpen.
+func (e CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// ConflictingResourceError struct defines a resource that was not created
+// because a different resource with the same ID already exists.
+// Requested is the resource that was sent, Existing is the stored resource
+// and Fields contains the names of the fields that are different.
+// ConflictingResourceError matches ErrConflictingResource and the response error
+// that caused it using errors.Is.
+type ConflictingResourceError struct {
+	ID        string
+	Requested interface{}
+	Existing  interface{}
+	Fields    []string
+	CausedBy  error
+}
+
+// Error returns error string response for ConflictingResourceError.
+func (e ConflictingResourceError) Error() string {
+	return fmt.Sprintf("resource %s already exists with different fields: %s, caused by: %s",
+		e.ID, strings.Join(e.Fields, ", "), e.CausedBy)
+}
+
+// Unwrap returns ErrConflictingResource and the response error that caused ConflictingResourceError.
+func (e ConflictingResourceError) Unwrap() []error {
+	return []error{ErrConflictingResource, e.CausedBy}
+}
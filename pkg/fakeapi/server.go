@@ -0,0 +1,400 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakeapi provides an in-memory implementation of the account api
+// that can be used to test the client without the real fake-api service.
+package fakeapi
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// constants describing the fake api paths and its default page size.
+const (
+	BasePath        = "/v1"
+	AccountPath     = BasePath + "/organisation/accounts"
+	DefaultPageSize = 100
+)
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Server struct is an httptest server that keeps the account resources in memory.
+// Accounts are listed in creation order.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	accounts map[string]model.Account
+	order    []string
+	requests int
+}
+
+// NewServer function starts and returns a new Server,
+// the caller should call Close when it is no longer needed.
+func NewServer() *Server {
+	s := &Server{accounts: map[string]model.Account{}}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// BaseURL method returns the base api url, the value can be used
+// as BASE_API_URL environment variable or with client.WithBaseURL option.
+func (s *Server) BaseURL() string {
+	return s.URL + BasePath
+}
+
+// Accounts method returns all stored accounts in creation order.
+func (s *Server) Accounts() []model.Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := []model.Account{}
+	for _, id := range s.order {
+		list = append(list, s.accounts[id])
+	}
+	return list
+}
+
+// Reset method removes all stored accounts.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts = map[string]model.Account{}
+	s.order = nil
+}
+
+// ServeHTTP method handles the account api requests.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", strconv.Itoa(s.requests))
+
+	if r.URL.Path == AccountPath {
+		switch r.Method {
+		case http.MethodPost:
+			s.create(w, r)
+		case http.MethodGet:
+			s.list(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, AccountPath+"/")
+	if id == r.URL.Path || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "page not found")
+		return
+	}
+	if !uuidRegex.MatchString(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.fetch(w, r, id)
+	case http.MethodPatch:
+		s.update(w, r, id)
+	case http.MethodDelete:
+		s.delete(w, r, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// create method stores the request account.
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	acc, ok := readAccount(w, r)
+	if !ok {
+		return
+	}
+	if !uuidRegex.MatchString(acc.ID) || !uuidRegex.MatchString(acc.OrganisationID) {
+		writeError(w, http.StatusBadRequest, "validation failure list:\nid in body must be of type uuid")
+		return
+	}
+	if _, found := s.accounts[acc.ID]; found {
+		writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		return
+	}
+
+	now := time.Now().UTC()
+	acc.CreatedOn, acc.ModifiedOn, acc.Version = now, now, 0
+
+	s.accounts[acc.ID] = acc
+	s.order = append(s.order, acc.ID)
+	writeData(w, http.StatusCreated, acc, nil)
+}
+
+// fetch method returns the account with id and its ETag header,
+// if the If-None-Match header is the account ETag a not modified response is written.
+func (s *Server) fetch(w http.ResponseWriter, r *http.Request, id string) {
+	acc, found := s.accounts[id]
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+
+	etag := accountETag(acc)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeData(w, http.StatusOK, acc, nil)
+}
+
+// accountETag function returns the acc ETag, the hash of the account json.
+func accountETag(acc model.Account) string {
+	b, _ := json.Marshal(acc)
+	return fmt.Sprintf(`"%x"`, sha1.Sum(b))
+}
+
+// list method returns the accounts page requested by page[number] and page[size]
+// query params, or all accounts if page[number] is not given.
+// The accounts are selected by the filter[organisation_id], filter[bank_id], filter[account_number],
+// filter[iban], filter[country] and filter[customer_id] query params and sorted
+// by the sort query param fields: id, created_on or modified_on.
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	all := s.selected(q)
+	pageNum, pageSize := 0, len(all)
+
+	if v := q.Get("page[number]"); len(v) != 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid page number")
+			return
+		}
+		pageNum, pageSize = n, DefaultPageSize
+	}
+	if v := q.Get("page[size]"); len(v) != 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid page size")
+			return
+		}
+		pageSize = n
+	}
+
+	list := []model.Account{}
+	links := map[string]string{"self": r.URL.RequestURI()}
+	if pageSize == 0 {
+		writeData(w, http.StatusOK, list, links)
+		return
+	}
+
+	start := pageNum * pageSize
+	for i := start; i < len(all) && i < start+pageSize; i++ {
+		list = append(list, all[i])
+	}
+
+	last := 0
+	if len(all) != 0 {
+		last = (len(all) - 1) / pageSize
+	}
+	links["first"] = pageURL(q, 0, pageSize)
+	links["last"] = pageURL(q, last, pageSize)
+	if pageNum < last {
+		links["next"] = pageURL(q, pageNum+1, pageSize)
+	}
+	if pageNum > 0 {
+		links["prev"] = pageURL(q, pageNum-1, pageSize)
+	}
+	writeData(w, http.StatusOK, list, links)
+}
+
+// selected method returns the accounts matching the q query filters,
+// sorted by the q sort fields.
+func (s *Server) selected(q url.Values) []model.Account {
+	filters := map[string]func(a model.Account) string{
+		"organisation_id": func(a model.Account) string { return a.OrganisationID },
+		"bank_id":         func(a model.Account) string { return a.Attributes.BankID },
+		"account_number":  func(a model.Account) string { return a.Attributes.AccountNumber },
+		"iban":            func(a model.Account) string { return a.Attributes.Iban },
+		"country":         func(a model.Account) string { return a.Attributes.Country },
+		"customer_id":     func(a model.Account) string { return a.Attributes.CustomerID },
+	}
+
+	list := []model.Account{}
+	for _, id := range s.order {
+		acc, match := s.accounts[id], true
+		for name, field := range filters {
+			if v := q.Get("filter[" + name + "]"); len(v) != 0 {
+				match = match && contains(strings.Split(v, ","), field(acc))
+			}
+		}
+		if match {
+			list = append(list, acc)
+		}
+	}
+
+	if sort := q.Get("sort"); len(sort) != 0 {
+		sortAccounts(list, strings.Split(sort, ","))
+	}
+	return list
+}
+
+// sortAccounts function sorts list by fields, a field prefixed with "-" is sorted descending.
+func sortAccounts(list []model.Account, fields []string) {
+	sort.SliceStable(list, func(i, j int) bool {
+		for _, f := range fields {
+			desc := strings.HasPrefix(f, "-")
+			if c := compare(list[i], list[j], strings.TrimPrefix(f, "-")); c != 0 {
+				return (c < 0) != desc
+			}
+		}
+		return false
+	})
+}
+
+// compare function compares a and b accounts by the field value.
+func compare(a, b model.Account, field string) int {
+	switch field {
+	case "id":
+		return strings.Compare(a.ID, b.ID)
+	case "created_on":
+		return compareTime(a.CreatedOn, b.CreatedOn)
+	case "modified_on":
+		return compareTime(a.ModifiedOn, b.ModifiedOn)
+	}
+	return 0
+}
+
+// compareTime function compares a and b times.
+func compareTime(a, b time.Time) int {
+	if a.Before(b) {
+		return -1
+	} else if a.After(b) {
+		return 1
+	}
+	return 0
+}
+
+// contains function checks if values contains v.
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// update method amends the account with id if the request account
+// has the current version, the account version is incremented.
+func (s *Server) update(w http.ResponseWriter, r *http.Request, id string) {
+	acc, ok := readAccount(w, r)
+	if !ok {
+		return
+	}
+
+	cur, found := s.accounts[id]
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+	if acc.Version != cur.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	cur.Attributes = acc.Attributes
+	cur.Version++
+	cur.ModifiedOn = time.Now().UTC()
+
+	s.accounts[id] = cur
+	writeData(w, http.StatusOK, cur, nil)
+}
+
+// delete method removes the account with id if version query param
+// is the account current version.
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, id string) {
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+
+	acc, found := s.accounts[id]
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+	if acc.Version != version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	delete(s.accounts, id)
+	for i, oid := range s.order {
+		if oid == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readAccount function decodes the request body account,
+// if the body cannot be decoded a bad request response is written.
+func readAccount(w http.ResponseWriter, r *http.Request) (model.Account, bool) {
+	body := struct {
+		Data model.Account `json:"data"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return model.Account{}, false
+	}
+	return body.Data, true
+}
+
+// pageURL function returns the url of the accounts page using the q query params.
+func pageURL(q url.Values, pageNum, pageSize int) string {
+	pq := url.Values{}
+	for k, v := range q {
+		pq[k] = v
+	}
+	pq.Set("page[number]", strconv.Itoa(pageNum))
+	pq.Set("page[size]", strconv.Itoa(pageSize))
+	return AccountPath + "?" + pq.Encode()
+}
+
+// writeData function writes data and links as response body.
+func writeData(w http.ResponseWriter, code int, data interface{}, links map[string]string) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "links": links})
+}
+
+// writeError function writes an error_message response body.
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error_message": msg})
+}
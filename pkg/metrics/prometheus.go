@@ -0,0 +1,228 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics contains the metrics collectors that can be used
+// with the client.WithMetrics option.
+package metrics
+
+import (
+	"fmt"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultNamespace is the default prefix of the metric names.
+const DefaultNamespace = "fake_api_client"
+
+// DefaultBuckets function returns the default latency histogram buckets in seconds.
+func DefaultBuckets() []float64 {
+	return []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+}
+
+// labels struct keeps the metric label values.
+type labels struct {
+	operation string
+	method    string
+	status    string
+}
+
+// histogram struct keeps the latency histogram of a labels set.
+// counts contains the observations count of every bucket, not cumulated.
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Prometheus struct is a client.MetricsCollector that keeps the request metrics
+// in memory and writes them using the Prometheus text exposition format.
+// The metrics are <namespace>_requests_total, <namespace>_request_errors_total
+// and <namespace>_request_duration_seconds, labelled by operation, method and status code.
+// The responses read from the client cache are counted only by <namespace>_cache_hits_total,
+// so they do not change the request counts and latencies.
+// A Prometheus object is its own registry, so it can be tested without a global state,
+// it is safe to be used by more goroutines.
+type Prometheus struct {
+	namespace string
+	buckets   []float64
+
+	mu        sync.Mutex
+	requests  map[labels]uint64
+	errors    map[labels]uint64
+	durations map[labels]*histogram
+	cacheHits map[labels]uint64
+}
+
+// NewPrometheus function returns a Prometheus collector using namespace as metric
+// names prefix and buckets as latency histogram buckets in seconds.
+// If namespace is empty DefaultNamespace is used,
+// if buckets is empty DefaultBuckets are used.
+func NewPrometheus(namespace string, buckets []float64) *Prometheus {
+	if len(namespace) == 0 {
+		namespace = DefaultNamespace
+	}
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets()
+	}
+
+	b := append([]float64{}, buckets...)
+	sort.Float64s(b)
+	return &Prometheus{
+		namespace: namespace,
+		buckets:   b,
+		requests:  map[labels]uint64{},
+		errors:    map[labels]uint64{},
+		durations: map[labels]*histogram{},
+		cacheHits: map[labels]uint64{},
+	}
+}
+
+// ObserveRequest method records the o request.
+func (p *Prometheus) ObserveRequest(o client.RequestObservation) {
+	l := labels{operation: o.Operation, method: o.Method, status: strconv.Itoa(o.StatusCode)}
+	sec := o.Duration.Seconds()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if o.CacheHit {
+		p.cacheHits[l]++
+		return
+	}
+
+	p.requests[l]++
+	if o.Err != nil {
+		p.errors[l]++
+	}
+
+	h, ok := p.durations[l]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.durations[l] = h
+	}
+	for i, b := range p.buckets {
+		if sec <= b {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += sec
+	h.count++
+}
+
+// Requests method returns the number of requests recorded for the labels.
+func (p *Prometheus) Requests(operation, method string, status int) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests[labels{operation: operation, method: method, status: strconv.Itoa(status)}]
+}
+
+// Errors method returns the number of failed requests recorded for the labels.
+func (p *Prometheus) Errors(operation, method string, status int) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.errors[labels{operation: operation, method: method, status: strconv.Itoa(status)}]
+}
+
+// CacheHits method returns the number of responses read from the client cache for the labels.
+func (p *Prometheus) CacheHits(operation, method string, status int) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cacheHits[labels{operation: operation, method: method, status: strconv.Itoa(status)}]
+}
+
+// WriteTo method writes all metrics to w using the Prometheus text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sb := &strings.Builder{}
+
+	name := p.namespace + "_requests_total"
+	fmt.Fprintf(sb, "# HELP %s Total number of account api requests.\n# TYPE %s counter\n", name, name)
+	for _, l := range sortedLabels(p.requests) {
+		fmt.Fprintf(sb, "%s{%s} %d\n", name, l.format(), p.requests[l])
+	}
+
+	name = p.namespace + "_request_errors_total"
+	fmt.Fprintf(sb, "# HELP %s Total number of failed account api requests.\n# TYPE %s counter\n", name, name)
+	for _, l := range sortedLabels(p.errors) {
+		fmt.Fprintf(sb, "%s{%s} %d\n", name, l.format(), p.errors[l])
+	}
+
+	name = p.namespace + "_cache_hits_total"
+	fmt.Fprintf(sb, "# HELP %s Total number of account api responses read from the client cache.\n# TYPE %s counter\n", name, name)
+	for _, l := range sortedLabels(p.cacheHits) {
+		fmt.Fprintf(sb, "%s{%s} %d\n", name, l.format(), p.cacheHits[l])
+	}
+
+	name = p.namespace + "_request_duration_seconds"
+	fmt.Fprintf(sb, "# HELP %s Account api request latency in seconds.\n# TYPE %s histogram\n", name, name)
+	for _, l := range sortedLabels(p.durations) {
+		h := p.durations[l]
+		var cum uint64
+		for i, b := range p.buckets {
+			cum += h.counts[i]
+			fmt.Fprintf(sb, "%s_bucket{%s,le=\"%s\"} %d\n", name, l.format(),
+				strconv.FormatFloat(b, 'g', -1, 64), cum)
+		}
+		fmt.Fprintf(sb, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, l.format(), h.count)
+		fmt.Fprintf(sb, "%s_sum{%s} %s\n", name, l.format(), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(sb, "%s_count{%s} %d\n", name, l.format(), h.count)
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// ServeHTTP method writes all metrics as a Prometheus scrape response,
+// so the collector can be registered as a /metrics http handler.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// labelEscaper escapes the label values as the exposition format specifies,
+// only backslash, double quote and line feed are escaped.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// format method returns the labels in the exposition format.
+func (l labels) format() string {
+	return fmt.Sprintf(`operation="%s",method="%s",status="%s"`,
+		labelEscaper.Replace(l.operation), labelEscaper.Replace(l.method), labelEscaper.Replace(l.status))
+}
+
+// sortedLabels function returns the m keys sorted, so the metrics
+// are always written in the same order.
+func sortedLabels[V any](m map[labels]V) []labels {
+	res := make([]labels, 0, len(m))
+	for l := range m {
+		res = append(res, l)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].operation != res[j].operation {
+			return res[i].operation < res[j].operation
+		}
+		if res[i].method != res[j].method {
+			return res[i].method < res[j].method
+		}
+		return res[i].status < res[j].status
+	})
+	return res
+}
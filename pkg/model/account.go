@@ -15,6 +15,8 @@
 package model
 
 import (
+	"reflect"
+	"strings"
 	"time"
 )
 
@@ -30,17 +32,101 @@ type Account struct {
 }
 
 // Attributes struct is used for defining Account resource attributes.
+// Optional attributes are omitted from the request body when they have the zero value,
+// false for the boolean attributes, which is the api default. Only the identification
+// objects are pointers, so an empty object is not sent.
 type Attributes struct {
-	AccountNumber               string   `json:"account_number"`
-	AccountClassification       string   `json:"account_classification"`
-	AccountMatchingOptOut       bool     `json:"account_matching_opt_out"`
-	AlternativeBankAccountNames []string `json:"alternative_bank_account_names"`
-	BankID                      string   `json:"bank_id"`
-	BankIDCode                  string   `json:"bank_id_code"`
-	BaseCurrency                string   `json:"base_currency"`
-	Bic                         string   `json:"bic"`
-	Country                     string   `json:"country"`
-	CustomerID                  string   `json:"customer_id"`
-	JointAccount                bool     `json:"joint_account"`
-	Iban                        string   `json:"iban"`
-}
\ No newline at end of file
+	AccountNumber               string                      `json:"account_number,omitempty"`
+	AccountClassification       string                      `json:"account_classification,omitempty"`
+	AccountMatchingOptOut       bool                        `json:"account_matching_opt_out,omitempty"`
+	AlternativeBankAccountNames []string                    `json:"alternative_bank_account_names,omitempty"`
+	BankID                      string                      `json:"bank_id,omitempty"`
+	BankIDCode                  string                      `json:"bank_id_code,omitempty"`
+	BaseCurrency                string                      `json:"base_currency,omitempty"`
+	Bic                         string                      `json:"bic,omitempty"`
+	Country                     string                      `json:"country"`
+	CustomerID                  string                      `json:"customer_id,omitempty"`
+	JointAccount                bool                        `json:"joint_account,omitempty"`
+	Iban                        string                      `json:"iban,omitempty"`
+	Name                        []string                    `json:"name,omitempty"`
+	AlternativeNames            []string                    `json:"alternative_names,omitempty"`
+	SecondaryIdentification     string                      `json:"secondary_identification,omitempty"`
+	Status                      string                      `json:"status,omitempty"`
+	StatusReason                string                      `json:"status_reason,omitempty"`
+	Switched                    bool                        `json:"switched,omitempty"`
+	ProcessingService           string                      `json:"processing_service,omitempty"`
+	UserDefinedInformation      string                      `json:"user_defined_information,omitempty"`
+	ValidationType              string                      `json:"validation_type,omitempty"`
+	ReferenceMask               string                      `json:"reference_mask,omitempty"`
+	AcceptanceQualifier         string                      `json:"acceptance_qualifier,omitempty"`
+	PrivateIdentification       *PrivateIdentification      `json:"private_identification,omitempty"`
+	OrganisationIdentification  *OrganisationIdentification `json:"organisation_identification,omitempty"`
+}
+
+// PrivateIdentification struct is used for defining the identification
+// of the account holder when the account holder is a person.
+type PrivateIdentification struct {
+	BirthDate      string   `json:"birth_date,omitempty"`
+	BirthCountry   string   `json:"birth_country,omitempty"`
+	Identification string   `json:"identification,omitempty"`
+	Address        []string `json:"address,omitempty"`
+	City           string   `json:"city,omitempty"`
+	Country        string   `json:"country,omitempty"`
+}
+
+// OrganisationIdentification struct is used for defining the identification
+// of the account holder when the account holder is an organisation.
+type OrganisationIdentification struct {
+	Identification string   `json:"identification,omitempty"`
+	Actors         []Actor  `json:"actors,omitempty"`
+	Address        []string `json:"address,omitempty"`
+	City           string   `json:"city,omitempty"`
+	Country        string   `json:"country,omitempty"`
+}
+
+// Actor struct is used for defining a person that acts
+// on behalf of the account holder organisation.
+type Actor struct {
+	Name      []string `json:"name,omitempty"`
+	BirthDate string   `json:"birth_date,omitempty"`
+	Residency string   `json:"residency,omitempty"`
+}
+
+// Account status values.
+const (
+	AccountStatusPending   = "pending"
+	AccountStatusConfirmed = "confirmed"
+	AccountStatusFailed    = "failed"
+)
+
+// Diff method returns the json names of the a account fields that are set
+// and different in b, like "attributes.iban". The fields not set in a are
+// not compared, so the fields filled in by the server, like status, created_on,
+// modified_on and version, do not make a retried request differ from the stored account.
+func (a Account) Diff(b Account) []string {
+	fields := []string{}
+	for _, f := range []struct {
+		name string
+		x, y string
+	}{
+		{"id", a.ID, b.ID},
+		{"organisation_id", a.OrganisationID, b.OrganisationID},
+		{"type", a.Type, b.Type},
+	} {
+		if len(f.x) != 0 && f.x != f.y {
+			fields = append(fields, f.name)
+		}
+	}
+
+	x, y := reflect.ValueOf(a.Attributes), reflect.ValueOf(b.Attributes)
+	for i := 0; i < x.NumField(); i++ {
+		if x.Field(i).IsZero() {
+			continue
+		}
+		if !reflect.DeepEqual(x.Field(i).Interface(), y.Field(i).Interface()) {
+			name := strings.Split(x.Type().Field(i).Tag.Get("json"), ",")[0]
+			fields = append(fields, "attributes."+name)
+		}
+	}
+	return fields
+}
@@ -14,5 +14,12 @@
 
 package model
 
+// Resource interface is implemented by every api resource type.
 type Resource interface {
 }
+
+// Validator interface is implemented by the resources
+// that can be validated before they are sent to the server.
+type Validator interface {
+	Validate() error
+}
@@ -0,0 +1,152 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var (
+	uuidRegex          = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	bicRegex           = regexp.MustCompile(`^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$`)
+	ibanRegex          = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{0,64}$`)
+	accountNumberRegex = regexp.MustCompile(`^[A-Z0-9]{0,64}$`)
+	bankIDRegex        = regexp.MustCompile(`^[A-Z0-9]{0,16}$`)
+	bankIDCodeRegex    = regexp.MustCompile(`^[A-Z]{0,16}$`)
+)
+
+// countryRule struct describes the bank id and account number rules of a country.
+// A zero max length means that the field is not supported by the country.
+type countryRule struct {
+	bankIDRequired   bool
+	bankIDMin        int
+	bankIDMax        int
+	bankIDCode       string
+	bicRequired      bool
+	accountNumberMin int
+	accountNumberMax int
+}
+
+// countryRules contains the rules of the countries supported by the account api.
+var countryRules = map[string]countryRule{
+	"AU": {false, 6, 6, "AUBSB", true, 6, 10},
+	"BE": {true, 3, 3, "BE", false, 7, 7},
+	"CA": {false, 9, 9, "CACPA", true, 7, 12},
+	"CH": {true, 5, 5, "CHBCC", false, 12, 12},
+	"DE": {true, 8, 8, "DEBLZ", false, 7, 7},
+	"ES": {true, 8, 8, "ESNCC", false, 10, 10},
+	"FR": {true, 10, 10, "FR", false, 10, 10},
+	"GB": {true, 6, 6, "GBDSC", true, 8, 8},
+	"GR": {true, 7, 7, "GRBIC", false, 16, 16},
+	"HK": {false, 3, 3, "HKNCC", true, 9, 12},
+	"IT": {true, 10, 11, "ITNCC", false, 12, 12},
+	"LU": {true, 3, 3, "LULUX", false, 13, 13},
+	"NL": {false, 0, 0, "", true, 10, 10},
+	"PL": {true, 8, 8, "PLKNR", false, 16, 16},
+	"PT": {true, 8, 8, "PTNCC", false, 11, 11},
+	"US": {true, 9, 9, "USABA", true, 6, 17},
+}
+
+// Validate method checks the account fields before the account is sent to the server.
+// The method returns ValidationError containing every field that is not valid,
+// or nil if the account is valid.
+func (a Account) Validate() error {
+	v := validation{}
+
+	v.check(uuidRegex.MatchString(a.ID), "id", "must be of type uuid: %q", a.ID)
+	v.check(uuidRegex.MatchString(a.OrganisationID), "organisation_id", "must be of type uuid: %q", a.OrganisationID)
+	v.check(a.Type == "accounts", "type", "should be one of [accounts]")
+
+	at := a.Attributes
+	v.check(countryCodes[at.Country], "country", "must be an ISO 3166-1 country code: %q", at.Country)
+	v.check(len(at.BaseCurrency) == 0 || currencyCodes[at.BaseCurrency],
+		"base_currency", "must be an ISO 4217 currency code: %q", at.BaseCurrency)
+	v.check(len(at.Bic) == 0 || bicRegex.MatchString(at.Bic),
+		"bic", "should match '%s'", bicRegex)
+	v.check(len(at.Iban) == 0 || isValidIban(at.Iban),
+		"iban", "must be a valid iban with correct checksum: %q", at.Iban)
+	v.check(len(at.AccountClassification) == 0 ||
+		at.AccountClassification == "Personal" || at.AccountClassification == "Business",
+		"account_classification", "should be one of [Personal Business]")
+	v.check(accountNumberRegex.MatchString(at.AccountNumber),
+		"account_number", "should match '%s'", accountNumberRegex)
+	v.check(bankIDRegex.MatchString(at.BankID), "bank_id", "should match '%s'", bankIDRegex)
+	v.check(bankIDCodeRegex.MatchString(at.BankIDCode), "bank_id_code", "should match '%s'", bankIDCodeRegex)
+
+	if r, ok := countryRules[at.Country]; ok {
+		v.checkCountryRule(r, at)
+	}
+
+	if len(v.fields) != 0 {
+		return errors.ValidationError{
+			Message: fmt.Sprintf("account %s is not valid", a.ID),
+			Fields:  v.fields}
+	}
+	return nil
+}
+
+// validation struct is used to collect the fields that failed the validation.
+type validation struct {
+	fields []errors.FieldError
+}
+
+// check method adds a FieldError for field with the formatted message if ok is false.
+func (v *validation) check(ok bool, field, format string, args ...interface{}) {
+	if !ok {
+		v.fields = append(v.fields, errors.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// checkCountryRule method checks the bank id, bank id code, bic
+// and account number of at attributes regarding the country rule r.
+func (v *validation) checkCountryRule(r countryRule, at Attributes) {
+	if r.bankIDMax == 0 {
+		v.check(len(at.BankID) == 0, "bank_id", "is not supported for country %s", at.Country)
+	} else if len(at.BankID) != 0 || r.bankIDRequired {
+		v.check(len(at.BankID) >= r.bankIDMin && len(at.BankID) <= r.bankIDMax,
+			"bank_id", "should have between %d and %d characters for country %s", r.bankIDMin, r.bankIDMax, at.Country)
+	}
+
+	v.check(len(at.BankIDCode) == 0 || at.BankIDCode == r.bankIDCode,
+		"bank_id_code", "should be %q for country %s", r.bankIDCode, at.Country)
+	v.check(len(at.Bic) != 0 || !r.bicRequired, "bic", "is required for country %s", at.Country)
+	v.check(len(at.AccountNumber) == 0 ||
+		(len(at.AccountNumber) >= r.accountNumberMin && len(at.AccountNumber) <= r.accountNumberMax),
+		"account_number", "should have between %d and %d characters for country %s",
+		r.accountNumberMin, r.accountNumberMax, at.Country)
+}
+
+// isValidIban function checks the iban format and its ISO 13616 mod 97 checksum.
+func isValidIban(iban string) bool {
+	if !ibanRegex.MatchString(iban) {
+		return false
+	}
+
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(fmt.Sprint(r - 'A' + 10))
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
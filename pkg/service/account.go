@@ -16,38 +16,160 @@ package service
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
 	"encoding/json"
-	"github.com/pancudaniel7/fake-api-client/configs"
+	goerrors "errors"
+	"fmt"
 	_http "github.com/pancudaniel7/fake-api-client/internal/http"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
 	"github.com/pancudaniel7/fake-api-client/pkg/model"
 	"net/http"
 )
 
-type Account struct{}
+// maxDeleteLatestAttempts is the maximum number of fetch and delete
+// attempts made by DeleteLatest when the account version changes.
+const maxDeleteLatestAttempts = 3
+
+// Account struct is the service used to manipulate account resources.
+// An Account created without a client, like Account{},
+// uses the client.Default client.
+type Account struct {
+	client *client.Client
+}
+
+// NewAccount function returns an Account service that sends
+// every request using the c client.
+// Account operations are implemented using the Accounts generic Resource service.
+func NewAccount(c *client.Client) Account {
+	return Account{client: c}
+}
 
 // Create account method used for creating account resource type.
 // The method creates the request for creating account resource
-// or returns jsonError if it cannot parse the reqBody object,
+// or returns ValidationError if acc is a model.Validator that is not valid,
+// or jsonError if it cannot parse the reqBody object,
 // or RequestError if cannot create the request,
 // or it returns the account if the account was successful created.
 func (a Account) Create(acc model.Resource) (model.Resource, error) {
-	reqBody := _http.Body{Data: acc}
+	return a.CreateCtx(context.Background(), acc)
+}
+
+// CreateCtx method is the Create method that uses ctx context
+// to cancel the request or to set its deadline.
+// If ctx is done before the response is received CanceledError is returned.
+func (a Account) CreateCtx(ctx context.Context, acc model.Resource) (model.Resource, error) {
+	res, err := a.resource().create(ctx, acc)
+	if res == nil {
+		return nil, err
+	}
+	return res, err
+}
+
+// CreateIdempotent method creates the acc account like Create, but the request
+// is sent with a random IdempotencyKeyHeader so it can be retried by the client RetryPolicy.
+// If the account already exists (conflict response), for example because a previous
+// attempt timed out after the account was created, the existing account is fetched
+// like ListBy, bypassing the client Cache, and compared with acc using model.Account Diff,
+// so only the fields set in acc are compared. If they are identical the existing account is returned,
+// otherwise ConflictingResourceError is returned with both accounts.
+func (a Account) CreateIdempotent(acc model.Account) (model.Resource, error) {
+	return a.CreateIdempotentCtx(context.Background(), acc)
+}
+
+// CreateIdempotentCtx method is the CreateIdempotent method that uses ctx context
+// to cancel the requests or to set their deadline.
+// If ctx is done before the response is received CanceledError is returned.
+func (a Account) CreateIdempotentCtx(ctx context.Context, acc model.Account) (model.Resource, error) {
+	req, err := a.resource().createRequest(ctx, acc)
+	if err != nil {
+		return nil, err
+	}
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(_http.IdempotencyKeyHeader, key)
+
+	resAcc := &model.Account{}
+	err = a.api().SendRequest(req, http.StatusCreated, resAcc)
+	if !goerrors.Is(err, errors.ErrConflict) {
+		if err != nil {
+			return nil, err
+		}
+		return resAcc, nil
+	}
+	conflictErr := err
+
+	existing, err := a.resource().fetch(ctx, acc.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// the requested account is decoded like the existing one
+	// so the omitted and empty fields are compared as equal.
+	b, err := json.Marshal(acc)
+	if err != nil {
+		return nil, err
+	}
+	requested := model.Account{}
+	if err = json.Unmarshal(b, &requested); err != nil {
+		return nil, err
+	}
+
+	if fields := requested.Diff(*existing); len(fields) != 0 {
+		return nil, errors.ConflictingResourceError{
+			ID:        acc.ID,
+			Requested: acc,
+			Existing:  *existing,
+			Fields:    fields,
+			CausedBy:  conflictErr}
+	}
+	return existing, nil
+}
+
+// Update method amends the account entity with acc.ID using acc attributes.
+// acc.Version should be the account current version, the returned account
+// contains the new version.
+// The method returns ValidationError if acc is not valid,
+// or VersionConflictError if acc.Version is not the current version,
+// or RequestError if cannot create the request.
+func (a Account) Update(acc model.Account) (model.Resource, error) {
+	return a.UpdateCtx(context.Background(), acc)
+}
+
+// UpdateCtx method is the Update method that uses ctx context
+// to cancel the request or to set its deadline.
+// If ctx is done before the response is received CanceledError is returned.
+func (a Account) UpdateCtx(ctx context.Context, acc model.Account) (model.Resource, error) {
+	if err := acc.Validate(); err != nil {
+		return nil, err
+	}
 
-	b, err := json.Marshal(reqBody)
+	b, err := json.Marshal(_http.Body{Data: acc})
 	if err != nil {
 		return nil, err
 	}
 
-	reqUrl := configs.Properties().BaseAPIURL + _http.AccountPath
-	body := bytes.NewReader(b)
+	reqUrl := a.api().BaseURL + _http.AccountPath +
+		"/" + acc.ID
 
-	req, err := _http.CreateRequest(http.MethodPost, reqUrl, body)
+	ctx = a.resource().operation(ctx, "update")
+	req, err := _http.CreateRequest(ctx, http.MethodPatch, reqUrl, bytes.NewReader(b))
 	if err != nil {
 		return nil, err
 	}
 
 	resAcc := &model.Account{}
-	return resAcc, _http.APIClient().SendRequest(req, http.StatusCreated, resAcc)
+	err = a.api().SendRequest(req, http.StatusOK, resAcc)
+	if goerrors.Is(err, errors.ErrConflict) {
+		return nil, errors.VersionConflictError{ID: acc.ID, Version: acc.Version, CausedBy: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resAcc, nil
 }
 
 // List method returns all account list if pageNum and pageSize are empty,
@@ -55,57 +177,132 @@ func (a Account) Create(acc model.Resource) (model.Resource, error) {
 // Also this method returns RequestError if the request could not be created
 // or if the response returns an error content.
 func (a Account) List(pageNum, pageSize string) ([]model.Resource, error) {
+	return a.ListCtx(context.Background(), pageNum, pageSize)
+}
 
-	pagParam := _http.BuildPagination(pageNum, pageSize)
-	reqUrl := configs.Properties().BaseAPIURL + _http.AccountPath
-	if len(pagParam) != 0 {
-		reqUrl += "?" + pagParam
-	}
-
-	req, err := _http.CreateRequest(http.MethodGet, reqUrl, nil)
+// ListCtx method is the List method that uses ctx context
+// to cancel the request or to set its deadline.
+// If ctx is done before the response is received CanceledError is returned.
+func (a Account) ListCtx(ctx context.Context, pageNum, pageSize string) ([]model.Resource, error) {
+	accList, err := a.resource().List(ctx, pageNum, pageSize)
 	if err != nil {
 		return nil, err
 	}
 
-	resAccList := &[]model.Account{}
-	if err = _http.APIClient().SendRequest(req, http.StatusOK, resAccList); err != nil {
+	return convertSlicesAccountToResource(accList), nil
+}
+
+// ListWith method returns the account list selected by the opts filter,
+// pagination and sorting options, like AccountFilter.
+func (a Account) ListWith(ctx context.Context, opts ListOptions) ([]model.Resource, error) {
+	accList, err := a.resource().ListWith(ctx, opts)
+	if err != nil {
 		return nil, err
 	}
 
-	return convertSlicesAccountToResource(*resAccList), nil
+	return convertSlicesAccountToResource(accList), nil
 }
 
 // ListBy method returns one account entity requested by the account id.
+// If the client has a Cache the account can be returned from it, see client.WithCache.
 // Also this method returns RequestError if the request could not be created
 // or if the response returns an error content.
 func (a Account) ListBy(id string) (model.Resource, error) {
+	return a.ListByCtx(context.Background(), id)
+}
 
-	reqUrl := configs.Properties().BaseAPIURL + _http.AccountPath +
-		"/" + id
-
-	req, err := _http.CreateRequest(http.MethodGet, reqUrl, nil)
+// ListByCtx method is the ListBy method that uses ctx context
+// to cancel the request or to set its deadline.
+// If ctx is done before the response is received CanceledError is returned.
+func (a Account) ListByCtx(ctx context.Context, id string) (model.Resource, error) {
+	resAcc, err := a.resource().Fetch(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-
-	resAcc := &model.Account{}
-	return resAcc, _http.APIClient().SendRequest(req, http.StatusOK, resAcc)
+	return resAcc, nil
 }
 
-// DeleteBy method delete account entity by account id.
+// DeleteBy method delete account entity by account id
+// using the client record version.
 // Also this method returns RequestError if the request could not be created
 // or if the response returns an error content.
 func (a Account) DeleteBy(id string) error {
-	reqUrl := configs.Properties().BaseAPIURL + _http.AccountPath +
-		"/" + id +
-		"?" + _http.VersionLabel + configs.Properties().HttpRecordVersion
+	return a.DeleteByCtx(context.Background(), id)
+}
 
-	req, err := _http.CreateRequest(http.MethodDelete, reqUrl, nil)
-	if err != nil {
-		return err
+// DeleteByCtx method is the DeleteBy method that uses ctx context
+// to cancel the request or to set its deadline.
+// If ctx is done before the response is received CanceledError is returned.
+func (a Account) DeleteByCtx(ctx context.Context, id string) error {
+	return a.resource().delete(ctx, id, a.api().RecordVersion)
+}
+
+// DeleteByVersion method delete account entity by account id and record version.
+// If the version is not the account current version the response returns
+// an error content.
+func (a Account) DeleteByVersion(id string, version int) error {
+	return a.DeleteByVersionCtx(context.Background(), id, version)
+}
+
+// DeleteByVersionCtx method is the DeleteByVersion method that uses ctx context
+// to cancel the request or to set its deadline.
+func (a Account) DeleteByVersionCtx(ctx context.Context, id string, version int) error {
+	return a.resource().Delete(ctx, id, version)
+}
+
+// Delete method delete acc account entity using its ID and Version.
+func (a Account) Delete(acc model.Account) error {
+	return a.DeleteCtx(context.Background(), acc)
+}
+
+// DeleteCtx method is the Delete method that uses ctx context
+// to cancel the request or to set its deadline.
+func (a Account) DeleteCtx(ctx context.Context, acc model.Account) error {
+	return a.DeleteByVersionCtx(ctx, acc.ID, acc.Version)
+}
+
+// DeleteLatest method delete account entity by account id using its current version.
+// The method fetches the account in order to read its version and deletes it,
+// if the version was changed in the meantime (conflict or not found response)
+// the account is fetched again, at most maxDeleteLatestAttempts times.
+func (a Account) DeleteLatest(id string) error {
+	return a.DeleteLatestCtx(context.Background(), id)
+}
+
+// DeleteLatestCtx method is the DeleteLatest method that uses ctx context
+// to cancel the requests or to set their deadline.
+func (a Account) DeleteLatestCtx(ctx context.Context, id string) error {
+	_, err := a.deleteLatest(ctx, id, false)
+	return err
+}
+
+// deleteLatest method fetches the account with id and deletes it using its current version,
+// if dryRun is true the account is only fetched. The method returns the fetched account.
+// The account is not fetched from the client Cache in order to read its current version.
+func (a Account) deleteLatest(ctx context.Context, id string, dryRun bool) (*model.Account, error) {
+	var err error
+	for n := 0; n < maxDeleteLatestAttempts; n++ {
+		var acc *model.Account
+		if acc, err = a.resource().fetch(ctx, id, false); err != nil || dryRun {
+			return acc, err
+		}
+
+		err = a.DeleteCtx(ctx, *acc)
+		if !goerrors.Is(err, errors.ErrConflict) && !goerrors.Is(err, errors.ErrNotFound) {
+			return acc, err
+		}
 	}
+	return nil, err
+}
 
-	return _http.APIClient().SendRequest(req, http.StatusNoContent, nil)
+// resource method returns the generic Resource service used for account resources.
+func (a Account) resource() Resource[model.Account] {
+	return Accounts(a.client)
+}
+
+// api method returns the client used by the service to send requests.
+func (a Account) api() *client.Client {
+	return a.resource().api()
 }
 
 // convertSlicesAccountToResource helps with converting list of Account types list
@@ -117,3 +314,16 @@ func convertSlicesAccountToResource(accList []model.Account) []model.Resource {
 	}
 	return resAccRes
 }
+
+// newIdempotencyKey function returns a random version 4 uuid used as idempotency key.
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.RequestError{
+			Message:  fmt.Sprintf("fail to generate idempotency key: %s", err),
+			CausedBy: err}
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -14,7 +14,10 @@
 
 package service
 
-import "github.com/pancudaniel7/fake-api-client/pkg/model"
+import (
+	"context"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+)
 
 // ApiOperations interface is used to implement SOLID principles
 // but also polymorphism in the library code and outside of it.
@@ -25,3 +28,13 @@ type ApiOperations interface {
 	ListBy(id string) (model.Resource, error)
 	DeleteBy(id string) error
 }
+
+// ApiOperationsCtx interface describes the ApiOperations that use
+// a context to cancel requests or to set their deadlines.
+type ApiOperationsCtx interface {
+	ApiOperations
+	CreateCtx(ctx context.Context, resource model.Resource) (model.Resource, error)
+	ListCtx(ctx context.Context, pageNum, pageSize string) ([]model.Resource, error)
+	ListByCtx(ctx context.Context, id string) (model.Resource, error)
+	DeleteByCtx(ctx context.Context, id string) error
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"sync"
+)
+
+// DefaultBulkConcurrency is the number of concurrent requests
+// used by bulk operations when BulkOptions Concurrency is not set.
+const DefaultBulkConcurrency = 8
+
+// purgePageSize is the number of accounts per page listed by PurgeOrganisation.
+const purgePageSize = 100
+
+// BulkOptions struct describes how bulk operations are run.
+// Concurrency is the maximum number of concurrent requests.
+// If FailFast is true the first failed item cancels the items
+// that were not finished yet.
+// Progress, if it is not nil, is called after every finished item
+// with the number of finished items and the total number of items.
+type BulkOptions struct {
+	Concurrency int
+	FailFast    bool
+	Progress    func(done, total int)
+}
+
+// CreateResult struct describes the result of one account creation,
+// Account is the created account or nil if Err is not nil.
+type CreateResult struct {
+	Account *model.Account
+	Err     error
+}
+
+// CreateMany method creates the accounts concurrently using at most opts.Concurrency
+// requests at the same time. Every account is created using CreateCtx method.
+// The returned results have the same order as accounts.
+// If opts.FailFast is true the first error is also returned and the accounts that
+// were not created yet are canceled, otherwise the returned error is nil.
+func (a Account) CreateMany(ctx context.Context, accounts []model.Account, opts BulkOptions) ([]CreateResult, error) {
+	results := make([]CreateResult, len(accounts))
+
+	err := runBulk(ctx, len(accounts), opts, func(ctx context.Context, i int) error {
+		if ctx.Err() != nil {
+			results[i].Err = canceledItemError(ctx, "account "+accounts[i].ID)
+			return results[i].Err
+		}
+
+		res, err := a.CreateCtx(ctx, accounts[i])
+		if err != nil {
+			results[i].Err = err
+			return err
+		}
+		results[i].Account = res.(*model.Account)
+		return nil
+	})
+	return results, err
+}
+
+// DeleteOptions struct describes how bulk delete operations are run.
+// If DryRun is true no account is deleted, the operation only returns
+// the accounts that would be deleted.
+type DeleteOptions struct {
+	BulkOptions
+	DryRun bool
+}
+
+// DeleteResult struct describes the result of one account deletion,
+// Account is the deleted account, with the version used to delete it,
+// or nil if the account could not be found.
+type DeleteResult struct {
+	ID      string
+	Account *model.Account
+	Err     error
+}
+
+// DeleteMany method deletes the accounts with ids concurrently using at most
+// opts.Concurrency requests at the same time. Every account is fetched in order
+// to resolve its current version and it is deleted like DeleteLatest method does.
+// The returned results have the same order as ids.
+// If opts.FailFast is true the first error is also returned and the accounts that
+// were not deleted yet are canceled, otherwise the returned error is nil.
+func (a Account) DeleteMany(ctx context.Context, ids []string, opts DeleteOptions) ([]DeleteResult, error) {
+	results := make([]DeleteResult, len(ids))
+
+	err := runBulk(ctx, len(ids), opts.BulkOptions, func(ctx context.Context, i int) error {
+		results[i].ID = ids[i]
+		if ctx.Err() != nil {
+			results[i].Err = canceledItemError(ctx, "account "+ids[i])
+			return results[i].Err
+		}
+
+		results[i].Account, results[i].Err = a.deleteLatest(ctx, ids[i], opts.DryRun)
+		return results[i].Err
+	})
+	return results, err
+}
+
+// PurgeOrganisation method deletes concurrently all the accounts of the orgID organisation
+// accepted by the filter function, if filter is nil every organisation account is deleted.
+// All the accounts are listed before any of them is deleted, using the filter[organisation_id]
+// query param and purgePageSize accounts per page, and they are deleted using their listed version.
+// The returned results have the accounts listing order.
+// If the accounts cannot be listed the listing error is returned.
+func (a Account) PurgeOrganisation(ctx context.Context, orgID string, filter func(acc model.Account) bool, opts DeleteOptions) ([]DeleteResult, error) {
+	accounts := []model.Account{}
+	it := a.IterateWith(ctx, ListOptions{
+		PageSize: purgePageSize,
+		Filter:   AccountFilter{OrganisationID: []string{orgID}},
+	})
+	for it.Next() {
+		acc := it.Value()
+		if acc.OrganisationID == orgID && (filter == nil || filter(acc)) {
+			accounts = append(accounts, acc)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]DeleteResult, len(accounts))
+	err := runBulk(ctx, len(accounts), opts.BulkOptions, func(ctx context.Context, i int) error {
+		results[i] = DeleteResult{ID: accounts[i].ID, Account: &accounts[i]}
+		if ctx.Err() != nil {
+			results[i].Err = canceledItemError(ctx, "account "+accounts[i].ID)
+		} else if !opts.DryRun {
+			results[i].Err = a.DeleteCtx(ctx, accounts[i])
+		}
+		return results[i].Err
+	})
+	return results, err
+}
+
+// runBulk function calls f for every item index from 0 to n using at most
+// opts.Concurrency goroutines. If opts.FailFast is true the first f error
+// cancels the ctx given to the other f calls and it is returned.
+// The items that were not started before ctx was done are still given to f
+// with the done context, so f can record their error.
+func runBulk(ctx context.Context, n int, opts BulkOptions, f func(ctx context.Context, i int) error) error {
+	workers := opts.Concurrency
+	if workers < 1 {
+		workers = DefaultBulkConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		done     int
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	jobs := make(chan int)
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				err := f(ctx, i)
+
+				mu.Lock()
+				done++
+				if err != nil && opts.FailFast && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				if opts.Progress != nil {
+					opts.Progress(done, n)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return firstErr
+}
+
+// canceledItemError function returns the CanceledError of an item
+// that was not processed because ctx is done.
+func canceledItemError(ctx context.Context, item string) error {
+	return errors.CanceledError{
+		Message:  fmt.Sprintf("%s was not processed: %s", item, ctx.Err()),
+		CausedBy: ctx.Err()}
+}
@@ -15,54 +15,171 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
 	"github.com/pancudaniel7/fake-api-client/pkg/model"
-	"sync"
+	"runtime/debug"
 )
 
-// resourcePromise struct is used to keep all needed objects in order to create
-// asynchronous promise functionality
-type resourcePromise struct {
-	wg  sync.WaitGroup
-	res model.Resource
-	err error
+// Future struct is used to get the result of a function called asynchronously.
+// The result can be awaited or handled by chained functions,
+// a panic of the called function is returned as PanicError.
+type Future[T any] struct {
+	done chan struct{}
+	res  T
+	err  error
 }
 
-// NewApiPromise returns a new promise and calls immediately the function f
-// in a new goroutine.
-// Also before the new goroutine method increments the wait group in order for being
-// able to reproduce asynchronous functionality.
-func NewApiPromise(f func(acc model.Resource) (model.Resource, error), args model.Account) *resourcePromise {
-	p := &resourcePromise{}
-
-	p.wg.Add(1)
+// NewFuture function returns a new future and calls immediately the f function
+// in a new goroutine. The future is completed with f result when f returns.
+func NewFuture[T any](f func() (T, error)) *Future[T] {
+	fu := &Future[T]{done: make(chan struct{})}
 	go func() {
-		p.res, p.err = f(args)
-		p.wg.Done()
+		defer close(fu.done)
+		defer func() {
+			if r := recover(); r != nil {
+				fu.err = errors.PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		fu.res, fu.err = f()
 	}()
-	return p
+	return fu
 }
 
-// Then method is used to call a f function if the promise call was successful.
-// The method waits for goroutine using wait group, and after that calls the successful
-// f function if err object is nil.
-func (p *resourcePromise) Then(r func(res model.Resource)) *resourcePromise {
-	go func() {
-		p.wg.Wait()
-		if p.err == nil {
-			r(p.res)
+// NewApiPromise returns a new future and calls immediately the function f
+// with args in a new goroutine, like a.Create for Account a.
+func NewApiPromise(f func(acc model.Resource) (model.Resource, error), args model.Resource) *Future[model.Resource] {
+	return NewFuture(func() (model.Resource, error) {
+		return f(args)
+	})
+}
+
+// Done method returns a channel that is closed when the future is completed.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await method waits for the future to complete and returns its result.
+// If ctx is done first the zero value and CanceledError are returned.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.res, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, errors.CanceledError{
+			Message:  fmt.Sprintf("future await was canceled: %s", ctx.Err()),
+			CausedBy: ctx.Err()}
+	}
+}
+
+// Then method returns a new future completed with the result of the r function
+// called with this future result if this future succeeded,
+// otherwise the new future is completed with this future error.
+func (f *Future[T]) Then(r func(res T) (T, error)) *Future[T] {
+	return Map(f, r)
+}
+
+// Catch method calls the e function with this future error if this future failed.
+// The returned future is completed with this future result after e returned.
+func (f *Future[T]) Catch(e func(err error)) *Future[T] {
+	return NewFuture(func() (T, error) {
+		<-f.done
+		if f.err != nil {
+			e(f.err)
 		}
-	}()
-	return p
+		return f.res, f.err
+	})
 }
 
-// Cache method is used to call an e function if the promise call failed.
-// The method waits for goroutine using wait group, and after that calls the failed
-// e function if err object is not nil.
-func (p *resourcePromise) Cache(e func(err error)) {
-	go func() {
-		p.wg.Wait()
-		if p.err != nil {
-			e(p.err)
+// Cache method is an alias of Catch method.
+//
+// Deprecated: use Catch instead.
+func (f *Future[T]) Cache(e func(err error)) *Future[T] {
+	return f.Catch(e)
+}
+
+// Map function returns a new future completed with the result of the r function
+// called with f future result if f succeeded, otherwise the new future
+// is completed with f error.
+func Map[T, U any](f *Future[T], r func(res T) (U, error)) *Future[U] {
+	return NewFuture(func() (U, error) {
+		<-f.done
+		if f.err != nil {
+			var zero U
+			return zero, f.err
 		}
-	}()
+		return r(f.res)
+	})
+}
+
+// All function returns a future completed with the results of all fs futures,
+// in the same order, or with the first error of the fs futures.
+func All[T any](fs ...*Future[T]) *Future[[]T] {
+	return NewFuture(func() ([]T, error) {
+		res := make([]T, len(fs))
+		errc := make(chan error, len(fs))
+		for i, f := range fs {
+			go func(i int, f *Future[T]) {
+				<-f.done
+				res[i] = f.res
+				errc <- f.err
+			}(i, f)
+		}
+
+		for range fs {
+			if err := <-errc; err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	})
+}
+
+// Any function returns a future completed with the result of the first
+// succeeded fs future, or with AggregateError if all fs futures failed.
+func Any[T any](fs ...*Future[T]) *Future[T] {
+	return NewFuture(func() (T, error) {
+		c := settled(fs)
+
+		errs := []error{}
+		for range fs {
+			f := <-c
+			if f.err == nil {
+				return f.res, nil
+			}
+			errs = append(errs, f.err)
+		}
+
+		var zero T
+		return zero, errors.AggregateError{Message: "all futures failed", Errors: errs}
+	})
+}
+
+// Race function returns a future completed with the result
+// of the first completed fs future.
+func Race[T any](fs ...*Future[T]) *Future[T] {
+	return NewFuture(func() (T, error) {
+		if len(fs) == 0 {
+			var zero T
+			return zero, errors.AggregateError{Message: "no futures to race"}
+		}
+
+		f := <-settled(fs)
+		return f.res, f.err
+	})
+}
+
+// settled function returns a channel that receives every fs future
+// in the order they are completed.
+func settled[T any](fs []*Future[T]) <-chan *Future[T] {
+	c := make(chan *Future[T], len(fs))
+	for _, f := range fs {
+		go func(f *Future[T]) {
+			<-f.done
+			c <- f
+		}(f)
+	}
+	return c
 }
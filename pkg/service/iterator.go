@@ -0,0 +1,120 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	_http "github.com/pancudaniel7/fake-api-client/internal/http"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"strconv"
+)
+
+// Iterator struct is used to walk lazily through all the resources of T type,
+// one page is requested only when the resources from the previous page were consumed.
+type Iterator[T any] struct {
+	ctx     context.Context
+	r       Resource[T]
+	opts    ListOptions
+	pageNum int
+	page    []T
+	cur     T
+	err     error
+	done    bool
+}
+
+// AccountIterator type is the Iterator used for account resources.
+type AccountIterator = Iterator[model.Account]
+
+// Iterate method returns an AccountIterator that lists the accounts
+// page by page using pageSize accounts per page, starting with the first page.
+// If pageSize is less than 1 the client page size is used.
+// The ctx context is used for every page request.
+func (a Account) Iterate(ctx context.Context, pageSize int) *AccountIterator {
+	return a.resource().Iterate(ctx, pageSize)
+}
+
+// IterateWith method returns an AccountIterator that lists the accounts
+// selected by the opts filter and sorting options page by page, see Resource.IterateWith.
+func (a Account) IterateWith(ctx context.Context, opts ListOptions) *AccountIterator {
+	return a.resource().IterateWith(ctx, opts)
+}
+
+// Iterate method returns an Iterator that lists the resources
+// page by page using pageSize resources per page, starting with the first page.
+// If pageSize is less than 1 the client page size is used.
+// The ctx context is used for every page request.
+func (r Resource[T]) Iterate(ctx context.Context, pageSize int) *Iterator[T] {
+	return r.IterateWith(ctx, ListOptions{PageSize: pageSize})
+}
+
+// IterateWith method returns an Iterator that lists the resources selected
+// by the opts filter and sorting options page by page, the filter is applied
+// by the server for every page. The iteration starts with opts.PageNumber page
+// using opts.PageSize resources per page, or the client page size if it is less than 1.
+// The ctx context is used for every page request.
+func (r Resource[T]) IterateWith(ctx context.Context, opts ListOptions) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, r: r, opts: opts, pageNum: opts.PageNumber}
+}
+
+// Next method advances the iterator to the next resource, which is returned by Value.
+// Next returns false when there are no more resources or when a page request failed,
+// in the last case the error is returned by Err.
+// The iteration stops when the server returns an empty page
+// or a page without next link.
+func (it *Iterator[T]) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+
+	it.cur = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Value method returns the current resource.
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err method returns the error that stopped the iteration,
+// or nil if the iteration was not stopped by an error.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// fetch method requests the next page of resources.
+func (it *Iterator[T]) fetch() {
+	pageSize := it.r.api().PageSize
+	if it.opts.PageSize > 0 {
+		pageSize = strconv.Itoa(it.opts.PageSize)
+	}
+
+	q := it.opts.Values(pageSize)
+	q.Set(_http.PageNumberParam, strconv.Itoa(it.pageNum))
+	q.Set(_http.PageSizeParam, pageSize)
+
+	page, links, err := it.r.listQuery(it.ctx, q)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	it.pageNum++
+	it.page = page
+	it.done = len(page) == 0 || len(links.Next) == 0
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	_http "github.com/pancudaniel7/fake-api-client/internal/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Filter interface is implemented by the resource filters used by ListOptions.
+// AddTo adds the filter query parameters to v.
+type Filter interface {
+	AddTo(v url.Values)
+}
+
+// AccountFilter struct defines the account list filters.
+// Every field accepts more values, an account matches the filter
+// if it has one of the values for every field that is set.
+type AccountFilter struct {
+	OrganisationID []string
+	BankID         []string
+	AccountNumber  []string
+	Iban           []string
+	Country        []string
+	CustomerID     []string
+}
+
+// AddTo method adds filter[organisation_id], filter[bank_id], filter[account_number],
+// filter[iban], filter[country] and filter[customer_id] query parameters to v.
+func (f AccountFilter) AddTo(v url.Values) {
+	addFilter(v, "organisation_id", f.OrganisationID)
+	addFilter(v, "bank_id", f.BankID)
+	addFilter(v, "account_number", f.AccountNumber)
+	addFilter(v, "iban", f.Iban)
+	addFilter(v, "country", f.Country)
+	addFilter(v, "customer_id", f.CustomerID)
+}
+
+// ListOptions struct defines the filter, pagination and sorting
+// used to list resources.
+// If PageNumber and PageSize are 0 all the resources are listed,
+// otherwise the PageNumber page is listed using PageSize resources per page,
+// or the client page size if PageSize is 0.
+// Sort contains the fields used to sort the resources,
+// a field prefixed with "-" is sorted descending, like "-created_on".
+type ListOptions struct {
+	PageNumber int
+	PageSize   int
+	Sort       []string
+	Filter     Filter
+}
+
+// Values method returns the query parameters of the list options,
+// defaultPageSize is used if only PageNumber is set.
+func (o ListOptions) Values(defaultPageSize string) url.Values {
+	v := url.Values{}
+	if o.PageNumber != 0 || o.PageSize != 0 {
+		v.Set(_http.PageNumberParam, strconv.Itoa(o.PageNumber))
+		v.Set(_http.PageSizeParam, defaultPageSize)
+		if o.PageSize != 0 {
+			v.Set(_http.PageSizeParam, strconv.Itoa(o.PageSize))
+		}
+	}
+	if len(o.Sort) != 0 {
+		v.Set(_http.SortParam, strings.Join(o.Sort, ","))
+	}
+	if o.Filter != nil {
+		o.Filter.AddTo(v)
+	}
+	return v
+}
+
+// addFilter function adds the name filter query parameter with values to v,
+// if values is empty no parameter is added.
+func addFilter(v url.Values, name string, values []string) {
+	if len(values) != 0 {
+		v.Set(fmt.Sprintf(_http.FilterParam, name), strings.Join(values, ","))
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	_http "github.com/pancudaniel7/fake-api-client/internal/http"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Resource struct is the generic service used to manipulate any api resource
+// of T type that is found at path, like "/organisation/accounts".
+// A Resource created without a client, like Resource[T]{}, uses the client.Default client.
+// The requests are labelled with the resource name and the operation, like "account.create".
+type Resource[T any] struct {
+	client *client.Client
+	path   string
+	name   string
+}
+
+// NewResource function returns a Resource service for the resources found at path,
+// every request is sent using the c client.
+// The resource name is the last path element without the plural "s",
+// like "account" for "/organisation/accounts".
+func NewResource[T any](c *client.Client, path string) Resource[T] {
+	name := strings.TrimSuffix(path[strings.LastIndex(path, "/")+1:], "s")
+	return Resource[T]{client: c, path: path, name: name}
+}
+
+// Accounts function returns the Resource service used for account resources.
+func Accounts(c *client.Client) Resource[model.Account] {
+	return NewResource[model.Account](c, _http.AccountPath)
+}
+
+// Create method creates the res resource and returns the created resource.
+// If res is a model.Validator that is not valid ValidationError is returned
+// and no request is sent.
+func (r Resource[T]) Create(ctx context.Context, res T) (*T, error) {
+	return r.create(ctx, res)
+}
+
+// Fetch method returns the resource with id.
+// If the client has a Cache the resource can be returned from it.
+func (r Resource[T]) Fetch(ctx context.Context, id string) (*T, error) {
+	return r.fetch(ctx, id, true)
+}
+
+// fetch method returns the resource with id, if cached is false
+// the resource is always requested from the server.
+func (r Resource[T]) fetch(ctx context.Context, id string, cached bool) (*T, error) {
+	ctx = r.operation(ctx, "fetch")
+	req, err := _http.CreateRequest(ctx, http.MethodGet, r.url()+"/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(T)
+	if cached {
+		err = r.api().SendCachedRequest(req, http.StatusOK, res)
+	} else {
+		err = r.api().SendRequest(req, http.StatusOK, res)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// List method returns the resources list by the pageNum and pageSize,
+// or all resources if pageNum is empty.
+func (r Resource[T]) List(ctx context.Context, pageNum, pageSize string) ([]T, error) {
+	list, _, err := r.listPage(ctx, pageNum, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Delete method deletes the resource with id and record version.
+func (r Resource[T]) Delete(ctx context.Context, id string, version int) error {
+	return r.delete(ctx, id, strconv.Itoa(version))
+}
+
+// create method creates the data resource, data can be any value
+// that is encoded as T resource.
+func (r Resource[T]) create(ctx context.Context, data interface{}) (*T, error) {
+	req, err := r.createRequest(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(T)
+	return res, r.api().SendRequest(req, http.StatusCreated, res)
+}
+
+// createRequest method returns the request used to create the data resource.
+func (r Resource[T]) createRequest(ctx context.Context, data interface{}) (*http.Request, error) {
+	if v, ok := data.(model.Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	b, err := json.Marshal(_http.Body{Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = r.operation(ctx, "create")
+	return _http.CreateRequest(ctx, http.MethodPost, r.url(), bytes.NewReader(b))
+}
+
+// delete method deletes the resource with id and version value.
+func (r Resource[T]) delete(ctx context.Context, id, version string) error {
+	reqUrl := r.url() + "/" + id + "?" + _http.VersionLabel + version
+
+	ctx = r.operation(ctx, "delete")
+	req, err := _http.CreateRequest(ctx, http.MethodDelete, reqUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	return r.api().SendRequest(req, http.StatusNoContent, nil)
+}
+
+// ListWith method returns the resources list selected by the opts filter,
+// pagination and sorting options.
+func (r Resource[T]) ListWith(ctx context.Context, opts ListOptions) ([]T, error) {
+	list, _, err := r.listQuery(ctx, opts.Values(r.api().PageSize))
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// listPage method returns the resources list by the pageNum and pageSize
+// together with the response pagination links.
+func (r Resource[T]) listPage(ctx context.Context, pageNum, pageSize string) ([]T, _http.Links, error) {
+	return r.listQuery(ctx, r.api().BuildPagination(pageNum, pageSize))
+}
+
+// listQuery method returns the resources list selected by the query parameters
+// together with the response pagination links.
+func (r Resource[T]) listQuery(ctx context.Context, query url.Values) ([]T, _http.Links, error) {
+	reqUrl := r.url()
+	if len(query) != 0 {
+		reqUrl += "?" + query.Encode()
+	}
+
+	ctx = r.operation(ctx, "list")
+	req, err := _http.CreateRequest(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, _http.Links{}, err
+	}
+
+	list := []T{}
+	links, err := r.api().SendListRequest(req, http.StatusOK, &list)
+	return list, links, err
+}
+
+// operation method returns a copy of ctx labelled with the op resource operation.
+func (r Resource[T]) operation(ctx context.Context, op string) context.Context {
+	return _http.WithOperation(ctx, r.name+"."+op)
+}
+
+// url method returns the resources url.
+func (r Resource[T]) url() string {
+	return r.api().BaseURL + r.path
+}
+
+// api method returns the client used by the service to send requests.
+func (r Resource[T]) api() *client.Client {
+	if r.client == nil {
+		return client.Default()
+	}
+	return r.client
+}
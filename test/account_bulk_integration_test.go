@@ -0,0 +1,170 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	goerrors "errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/metrics"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAccountBulkCreation(t *testing.T) {
+	expAccList := []model.Account{
+		readFileAsAccount("data/account.json"),
+		readFileAsAccount("data/invalid-account.json"),
+		readFileAsAccount("data/third-account.json"),
+		readFileAsAccount("data/fourth-account.json")}
+	a := service.Account{}
+
+	var progress int32
+	results, err := a.CreateMany(context.Background(), expAccList, service.BulkOptions{
+		Concurrency: 2,
+		Progress: func(done, total int) {
+			atomic.StoreInt32(&progress, int32(done))
+			assert.EqualValues(t, len(expAccList), total)
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, len(expAccList), atomic.LoadInt32(&progress))
+	assert.EqualValues(t, len(expAccList), len(results))
+
+	assert.IsType(t, errors.ValidationError{}, results[1].Err)
+	assert.Nil(t, results[1].Account)
+
+	for _, i := range []int{0, 2, 3} {
+		assert.Nil(t, results[i].Err)
+		assert.EqualValues(t, expAccList[i].ID, results[i].Account.ID)
+		deleteAccount(a, expAccList[i])
+	}
+}
+
+func TestFailAccountBulkCreationFailFast(t *testing.T) {
+	expAccList := []model.Account{
+		readFileAsAccount("data/invalid-account.json"),
+		readFileAsAccount("data/account.json"),
+		readFileAsAccount("data/second-account.json")}
+	a := service.Account{}
+
+	results, err := a.CreateMany(context.Background(), expAccList, service.BulkOptions{
+		Concurrency: 1,
+		FailFast:    true,
+	})
+
+	assert.IsType(t, errors.ValidationError{}, err)
+	assert.EqualValues(t, err, results[0].Err)
+
+	for i := 1; i < len(results); i++ {
+		assert.IsType(t, errors.CanceledError{}, results[i].Err)
+	}
+	assert.EqualValues(t, 0, len(listAllAccounts(t, a)))
+}
+
+// listAllAccounts function returns all the accounts stored by the api.
+func listAllAccounts(t *testing.T, a service.Account) []model.Resource {
+	list, err := a.List("", "")
+	assert.Nil(t, err)
+	return list
+}
+
+func TestAccountBulkDelete(t *testing.T) {
+	expAccList := []model.Account{
+		readFileAsAccount("data/account.json"),
+		readFileAsAccount("data/second-account.json")}
+	a := service.Account{}
+	createAccounts(a, expAccList)
+
+	missingID := "f3a1ad2c-4b8d-4a06-9c9b-4f2f0f0f0f0f"
+	ids := []string{expAccList[0].ID, missingID, expAccList[1].ID}
+
+	results, err := a.DeleteMany(context.Background(), ids, service.DeleteOptions{})
+
+	assert.Nil(t, err)
+	assert.Nil(t, results[0].Err)
+	assert.EqualValues(t, expAccList[0].ID, results[0].Account.ID)
+	assert.True(t, goerrors.Is(results[1].Err, errors.ErrNotFound))
+	assert.EqualValues(t, missingID, results[1].ID)
+	assert.Nil(t, results[2].Err)
+	assert.EqualValues(t, 0, len(listAllAccounts(t, a)))
+}
+
+func TestAccountOrganisationPurge(t *testing.T) {
+	expAccList := []model.Account{
+		readFileAsAccount("data/account.json"),
+		readFileAsAccount("data/second-account.json"),
+		readFileAsAccount("data/third-account.json")}
+	a := service.Account{}
+	createAccounts(a, expAccList)
+
+	isGP := func(acc model.Account) bool {
+		return acc.Attributes.Country == "GP"
+	}
+
+	results, err := a.PurgeOrganisation(context.Background(), testOrganisationID, isGP,
+		service.DeleteOptions{DryRun: true})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(results))
+	assert.EqualValues(t, expAccList[1].ID, results[0].ID)
+	assert.EqualValues(t, expAccList[2].ID, results[1].ID)
+	assert.EqualValues(t, 3, len(listAllAccounts(t, a)))
+
+	results, err = a.PurgeOrganisation(context.Background(), testOrganisationID, isGP,
+		service.DeleteOptions{BulkOptions: service.BulkOptions{Concurrency: 2}})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(results))
+	assert.EqualValues(t, 1, len(listAllAccounts(t, a)))
+
+	deleteAccount(a, expAccList[0])
+}
+
+func TestAccountOrganisationPurgeListsOnlyOrganisationAccounts(t *testing.T) {
+	orgAcc := readFileAsAccount("data/account.json")
+	otherAcc := readFileAsAccount("data/second-account.json")
+	otherAcc.OrganisationID = "c0ffee00-f30d-4bb1-84f8-b9fa04f6e748"
+
+	m := metrics.NewPrometheus(metrics.DefaultNamespace, nil)
+	a := service.NewAccount(client.New(client.WithMetrics(m)))
+	createAccounts(a, []model.Account{orgAcc, otherAcc})
+
+	results, err := a.PurgeOrganisation(context.Background(), testOrganisationID, nil,
+		service.DeleteOptions{DryRun: true})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(results))
+	assert.EqualValues(t, orgAcc.ID, results[0].ID)
+	assert.EqualValues(t, 1, m.Requests("account.list", "GET", 200))
+
+	deleteAccount(a, orgAcc)
+	deleteAccount(a, otherAcc)
+}
+
+// createAccounts function creates all accList accounts.
+func createAccounts(a service.Account, accList []model.Account) {
+	for _, acc := range accList {
+		if _, err := a.Create(acc); err != nil {
+			log.Fatalf("fail to create account resource: %s", err)
+		}
+	}
+}
@@ -12,18 +12,22 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration
-
 package test
 
 import (
+	"context"
 	"encoding/json"
+	"github.com/pancudaniel7/fake-api-client/configs"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
 	"github.com/pancudaniel7/fake-api-client/pkg/errors"
 	"github.com/pancudaniel7/fake-api-client/pkg/model"
 	"github.com/pancudaniel7/fake-api-client/pkg/service"
 	"github.com/stretchr/testify/assert"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestAccountCreation(t *testing.T) {
@@ -46,6 +50,25 @@ func TestAccountCreation(t *testing.T) {
 	deleteAccount(a, expAcc)
 }
 
+func TestAccountCreationWithAllAttributes(t *testing.T) {
+	expAcc := readFileAsAccount("data/full-account.json")
+	a := service.Account{}
+
+	resResource, err := a.Create(expAcc)
+	if err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+
+	actAcc := resResource.(*model.Account)
+
+	assert.EqualValues(t, expAcc.ID, actAcc.ID)
+	assert.EqualValues(t, expAcc.Attributes, actAcc.Attributes)
+	assert.EqualValues(t, model.AccountStatusConfirmed, actAcc.Attributes.Status)
+	assert.EqualValues(t, "Jeff Page", actAcc.Attributes.OrganisationIdentification.Actors[0].Name[0])
+
+	deleteAccount(a, expAcc)
+}
+
 func TestFailAccountCreationForSameId(t *testing.T) {
 	acc := readFileAsAccount("data/account.json")
 	a := service.Account{}
@@ -56,14 +79,20 @@ func TestFailAccountCreationForSameId(t *testing.T) {
 	}
 
 	expErr := errors.ResponseError{
-		StatusCode: 409,
-		Message:    "request error with different status code, expected: 201 but returned: 409 with error message: Account cannot be created as it violates a duplicate constraint",
-		CausedBy:   nil,
+		StatusCode:   409,
+		Message:      "request error with different status code, expected: 201 but returned: 409 with error message: Account cannot be created as it violates a duplicate constraint",
+		ErrorMessage: "Account cannot be created as it violates a duplicate constraint",
+		Method:       http.MethodPost,
+		URL:          configs.Properties().BaseAPIURL + "/organisation/accounts",
+		CausedBy:     nil,
 	}
 
 	_, actErr = a.Create(acc)
 
 	assert.NotNil(t, actErr)
+	assert.IsType(t, errors.ResponseError{}, actErr)
+	expErr.RequestID = actErr.(errors.ResponseError).RequestID
+
 	assert.EqualValues(t, expErr, actErr)
 
 	deleteAccount(a, acc)
@@ -73,25 +102,39 @@ func TestFailAccountCreationWithAllInvalidValues(t *testing.T) {
 	acc := readFileAsAccount("data/invalid-account.json")
 	a := service.Account{}
 
-	expErr := errors.ResponseError{
-		StatusCode: 400,
-		Message: "request error with different status code, " +
-			"expected: 201 but returned: 400 with error message: " +
-			"validation failure list:\nvalidation failure list:\nvalidation " +
-			"failure list:\naccount_classification in body should be one of " +
-			"[Personal Business]\naccount_number in body should match " +
-			"'^[A-Z0-9]{0,64}$'\nbank_id in body should match '^[A-Z0-9]{0,16}$'\nbank_id_code " +
-			"in body should match '^[A-Z]{0,16}$'\nbase_currency in body should match " +
-			"'^[A-Z]{3}$'\nbic in body should match '^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$'\ncountry " +
-			"in body should match '^[A-Z]{2}$'\niban in body should match '^[A-Z]{2}[0-9]{2}[A-Z0-9]{0,64}$'\nid " +
-			"in body must be of type uuid: \"invalid uuid\"\norganisation_id in body must be of type uuid: \"invalid organisation id\"\ntype " +
-			"in body should be one of [accounts]",
-		CausedBy: nil,
+	expFields := []string{"id", "organisation_id", "type", "country", "base_currency", "bic",
+		"iban", "account_classification", "account_number", "bank_id", "bank_id_code"}
+
+	_, actErr := a.Create(acc)
+
+	assert.IsType(t, errors.ValidationError{}, actErr)
+
+	actFields := []string{}
+	for _, f := range actErr.(errors.ValidationError).Fields {
+		actFields = append(actFields, f.Field)
 	}
+	assert.EqualValues(t, expFields, actFields)
+}
+
+func TestFailAccountCreationWithInvalidCountryRules(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	acc.Attributes.BankID = "4003"
+	acc.Attributes.Bic = ""
+	acc.Attributes.Iban = "GB34BUKB20201555555555"
+	a := service.Account{}
 
-	_, actArr := a.Create(acc)
+	expErr := errors.ValidationError{
+		Message: "account 3732611e-3106-440a-a50c-96d1db2a6d6a is not valid",
+		Fields: []errors.FieldError{
+			{Field: "iban", Message: "must be a valid iban with correct checksum: \"GB34BUKB20201555555555\""},
+			{Field: "bank_id", Message: "should have between 6 and 6 characters for country GB"},
+			{Field: "bic", Message: "is required for country GB"},
+		},
+	}
 
-	assert.EqualValues(t, expErr, actArr)
+	_, actErr := a.Create(acc)
+
+	assert.EqualValues(t, expErr, actErr)
 }
 
 func readFileAsAccount(path string) model.Account {
@@ -103,3 +146,146 @@ func readFileAsAccount(path string) model.Account {
 	}
 	return acc
 }
+
+func TestAccountCreationWithClient(t *testing.T) {
+	expAcc := readFileAsAccount("data/account.json")
+	c := client.New(
+		client.WithBaseURL(configs.Properties().BaseAPIURL),
+		client.WithTimeout(10*time.Second))
+	a := service.NewAccount(c)
+
+	resResource, err := a.Create(expAcc)
+	if err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+
+	actAcc := resResource.(*model.Account)
+
+	assert.EqualValues(t, expAcc.ID, actAcc.ID)
+	assert.EqualValues(t, expAcc.Attributes, actAcc.Attributes)
+
+	deleteAccount(a, expAcc)
+}
+
+func TestFailAccountCreationWithCanceledContext(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, actErr := a.CreateCtx(ctx, acc)
+
+	assert.IsType(t, errors.CanceledError{}, actErr)
+	assert.EqualValues(t, context.Canceled, actErr.(errors.CanceledError).CausedBy)
+}
+
+func TestAccountIdempotentCreationForExistingAccount(t *testing.T) {
+	expAcc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	if _, err := a.Create(expAcc); err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+
+	resResource, err := a.CreateIdempotent(expAcc)
+
+	assert.Nil(t, err)
+	actAcc := resResource.(*model.Account)
+	assert.EqualValues(t, expAcc.ID, actAcc.ID)
+	assert.EqualValues(t, expAcc.Attributes, actAcc.Attributes)
+
+	deleteAccount(a, expAcc)
+}
+
+func TestAccountIdempotentCreationIgnoresServerPopulatedFields(t *testing.T) {
+	expAcc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	// the stored account has the fields that the server fills in.
+	storedAcc := expAcc
+	storedAcc.Attributes.Status = model.AccountStatusConfirmed
+	storedAcc.Attributes.StatusReason = "unspecified"
+	if _, err := a.Create(storedAcc); err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+
+	resResource, err := a.CreateIdempotent(expAcc)
+
+	assert.Nil(t, err)
+	actAcc := resResource.(*model.Account)
+	assert.EqualValues(t, expAcc.ID, actAcc.ID)
+	assert.EqualValues(t, storedAcc.Attributes, actAcc.Attributes)
+
+	deleteAccount(a, expAcc)
+}
+
+func TestFailAccountIdempotentCreationForDifferentAccount(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	if _, err := a.Create(acc); err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+
+	reqAcc := acc
+	reqAcc.Attributes.CustomerID = "other-customer"
+	reqAcc.Attributes.AlternativeBankAccountNames = []string{"Other"}
+
+	_, actErr := a.CreateIdempotent(reqAcc)
+
+	assert.ErrorIs(t, actErr, errors.ErrConflictingResource)
+	assert.ErrorIs(t, actErr, errors.ErrConflict)
+	assert.IsType(t, errors.ConflictingResourceError{}, actErr)
+
+	conflictErr := actErr.(errors.ConflictingResourceError)
+	assert.EqualValues(t, acc.ID, conflictErr.ID)
+	assert.EqualValues(t, []string{"attributes.alternative_bank_account_names", "attributes.customer_id"}, conflictErr.Fields)
+	assert.EqualValues(t, reqAcc, conflictErr.Requested)
+	assert.EqualValues(t, acc.Attributes, conflictErr.Existing.(model.Account).Attributes)
+
+	deleteAccount(a, acc)
+}
+
+func TestAccountIdempotentCreationRetriesWithSameKey(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	keys := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error_message":"unavailable"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]model.Account{"data": acc})
+	}))
+	defer srv.Close()
+
+	p := client.DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.URL), client.WithRetryPolicy(p)))
+
+	resResource, err := a.CreateIdempotent(acc)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, acc.ID, resResource.(*model.Account).ID)
+	assert.EqualValues(t, 2, len(keys))
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, keys[0])
+	assert.EqualValues(t, keys[0], keys[1])
+}
+
+func TestAccountUnsetOptionalAttributesAreNotSent(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	acc.Attributes.JointAccount = true
+
+	b, err := json.Marshal(acc)
+	if err != nil {
+		log.Fatalf("fail to marshal account: %s", err)
+	}
+
+	assert.NotContains(t, string(b), "bank_id_code")
+	assert.NotContains(t, string(b), "alternative_bank_account_names")
+	assert.NotContains(t, string(b), "account_matching_opt_out")
+	assert.Contains(t, string(b), `"joint_account":true`)
+}
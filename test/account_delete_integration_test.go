@@ -12,16 +12,17 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration
-
 package test
 
 import (
+	goerrors "errors"
+	"github.com/pancudaniel7/fake-api-client/configs"
 	"github.com/pancudaniel7/fake-api-client/pkg/errors"
 	"github.com/pancudaniel7/fake-api-client/pkg/model"
 	"github.com/pancudaniel7/fake-api-client/pkg/service"
 	"github.com/stretchr/testify/assert"
 	"log"
+	"net/http"
 	"testing"
 )
 
@@ -52,17 +53,68 @@ func TestFailAccountDeleteWithInvalidId(t *testing.T) {
 	actErr := a.DeleteBy(acc.ID)
 
 	expErr := errors.ResponseError{
-		StatusCode: 400,
-		Message:    "request error with different status code, expected: 204 but returned: 400 with error message: id is not a valid uuid",
-		CausedBy:   nil,
+		StatusCode:   400,
+		Message:      "request error with different status code, expected: 204 but returned: 400 with error message: id is not a valid uuid",
+		ErrorMessage: "id is not a valid uuid",
+		Method:       http.MethodDelete,
+		URL:          configs.Properties().BaseAPIURL + "/organisation/accounts/wrong%20id%20value?version=0",
+		CausedBy:     nil,
 	}
 
+	assert.IsType(t, errors.ResponseError{}, actErr)
+	expErr.RequestID = actErr.(errors.ResponseError).RequestID
+
 	assert.EqualValues(t, expErr, actErr)
 
 	acc.ID = tempID
 	deleteAccount(a, acc)
 }
 
+func TestAccountDeleteByAccountVersion(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	res, err := a.Create(acc)
+	if err != nil {
+		log.Fatalf("fail to create first account resource: %s", err)
+	}
+
+	err = a.Delete(*res.(*model.Account))
+	assert.Nil(t, err)
+}
+
+func TestFailAccountDeleteByWrongVersion(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	_, err := a.Create(acc)
+	if err != nil {
+		log.Fatalf("fail to create first account resource: %s", err)
+	}
+
+	actErr := a.DeleteByVersion(acc.ID, 7)
+
+	assert.True(t, goerrors.Is(actErr, errors.ErrNotFound) || goerrors.Is(actErr, errors.ErrConflict))
+
+	deleteAccount(a, acc)
+}
+
+func TestAccountDeleteLatest(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	_, err := a.Create(acc)
+	if err != nil {
+		log.Fatalf("fail to create first account resource: %s", err)
+	}
+
+	err = a.DeleteLatest(acc.ID)
+	assert.Nil(t, err)
+
+	_, err = a.ListBy(acc.ID)
+	assert.True(t, goerrors.Is(err, errors.ErrNotFound))
+}
+
 func deleteAccount(a service.Account, acc model.Account) {
 	if err := a.DeleteBy(acc.ID); err != nil {
 		log.Fatalf("fail to delete account resource:  %s", err)
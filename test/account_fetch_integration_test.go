@@ -12,17 +12,19 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration
-
 package test
 
 import (
+	"context"
+	goerrors "errors"
 	"github.com/pancudaniel7/fake-api-client/pkg/errors"
 	"github.com/pancudaniel7/fake-api-client/pkg/model"
 	"github.com/pancudaniel7/fake-api-client/pkg/service"
 	"github.com/stretchr/testify/assert"
 	"log"
+	"net/http"
 	"testing"
+	"time"
 )
 
 func TestAccountFetchSuccessCreation(t *testing.T) {
@@ -32,7 +34,7 @@ func TestAccountFetchSuccessCreation(t *testing.T) {
 	doneChan := make(chan int)
 	p := service.NewApiPromise(a.Create, expAcc)
 
-	p.Then(func(res model.Resource) {
+	p.Then(func(res model.Resource) (model.Resource, error) {
 		actAcc := res.(*model.Account)
 
 		assert.EqualValues(t, expAcc.ID, actAcc.ID)
@@ -41,9 +43,10 @@ func TestAccountFetchSuccessCreation(t *testing.T) {
 		assert.EqualValues(t, expAcc.Attributes, actAcc.Attributes)
 
 		doneChan <- 1
+		return res, nil
 	})
 
-	p.Cache(func(err error) {
+	p.Catch(func(err error) {
 		log.Fatalf("Fail to fetch account creation: %s", err)
 	})
 	<-doneChan
@@ -58,13 +61,122 @@ func TestAccountFetchFailCreation(t *testing.T) {
 	doneChan := make(chan int)
 	p := service.NewApiPromise(a.Create, expAcc)
 
-	p.Then(func(res model.Resource) {
+	p.Then(func(res model.Resource) (model.Resource, error) {
 		log.Fatalf("Should fail to fetch account creation: %s", res)
+		return res, nil
 	})
 
 	p.Cache(func(err error) {
-		assert.EqualValues(t, err.(errors.ResponseError).StatusCode, 400)
+		assert.IsType(t, errors.ValidationError{}, err)
 		doneChan <- 1
 	})
 	<-doneChan
 }
+
+func TestAccountFetchAwaitChain(t *testing.T) {
+	expAcc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	p := service.NewApiPromise(a.Create, expAcc).
+		Then(func(res model.Resource) (model.Resource, error) {
+			return a.ListBy(res.(*model.Account).ID)
+		})
+	id := service.Map(p, func(res model.Resource) (string, error) {
+		return res.(*model.Account).ID, nil
+	})
+
+	actID, err := id.Await(context.Background())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, expAcc.ID, actID)
+
+	deleteAccount(a, expAcc)
+}
+
+func TestAccountFetchAll(t *testing.T) {
+	expAccList := []model.Account{
+		readFileAsAccount("data/account.json"),
+		readFileAsAccount("data/second-account.json"),
+		readFileAsAccount("data/third-account.json")}
+	a := service.Account{}
+
+	fs := []*service.Future[model.Resource]{}
+	for _, acc := range expAccList {
+		fs = append(fs, service.NewApiPromise(a.Create, acc))
+	}
+
+	resList, err := service.All(fs...).Await(context.Background())
+
+	assert.Nil(t, err)
+	for i, acc := range expAccList {
+		assert.EqualValues(t, acc.ID, resList[i].(*model.Account).ID)
+		deleteAccount(a, acc)
+	}
+}
+
+func TestAccountFetchAnyReturnsFirstSuccess(t *testing.T) {
+	fail := service.NewFuture(func() (int, error) {
+		return 0, goerrors.New("failed")
+	})
+	slow := service.NewFuture(func() (int, error) {
+		time.Sleep(50 * time.Millisecond)
+		return 2, nil
+	})
+
+	res, err := service.Any(fail, slow).Await(context.Background())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, res)
+}
+
+func TestAccountFetchRaceReturnsFirstSettled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fail := service.NewFuture(func() (int, error) {
+		return 0, goerrors.New("failed")
+	})
+	never := service.NewFuture(func() (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	_, err := service.Race(fail, never).Await(context.Background())
+
+	assert.EqualValues(t, "failed", err.Error())
+}
+
+func TestFailAccountFetchAnyWhenAllFail(t *testing.T) {
+	fail := service.NewFuture(func() (int, error) {
+		return 0, goerrors.New("failed")
+	})
+	notFound := service.NewFuture(func() (int, error) {
+		return 0, errors.ResponseError{StatusCode: http.StatusNotFound}
+	})
+
+	_, err := service.Any(fail, notFound).Await(context.Background())
+
+	assert.IsType(t, errors.AggregateError{}, err)
+	assert.True(t, goerrors.Is(err, errors.ErrNotFound))
+}
+
+func TestAccountFetchPanicAndCanceledAwait(t *testing.T) {
+	p := service.NewFuture(func() (int, error) {
+		panic("boom")
+	})
+
+	_, err := p.Await(context.Background())
+	assert.EqualValues(t, "boom", err.(errors.PanicError).Value)
+
+	stop, cancelStop := context.WithCancel(context.Background())
+	defer cancelStop()
+	never := service.NewFuture(func() (int, error) {
+		<-stop.Done()
+		return 0, stop.Err()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err = never.Await(ctx)
+	assert.True(t, goerrors.Is(err, context.DeadlineExceeded))
+}
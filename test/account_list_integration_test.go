@@ -12,16 +12,18 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration
-
 package test
 
 import (
+	"context"
+	goerrors "errors"
+	"github.com/pancudaniel7/fake-api-client/configs"
 	"github.com/pancudaniel7/fake-api-client/pkg/errors"
 	"github.com/pancudaniel7/fake-api-client/pkg/model"
 	"github.com/pancudaniel7/fake-api-client/pkg/service"
 	"github.com/stretchr/testify/assert"
 	"log"
+	"net/http"
 	"testing"
 )
 
@@ -199,13 +201,153 @@ func TestFailAccountListingByInvalidId(t *testing.T) {
 	_, actErr := a.ListBy(acc.ID)
 
 	expErr := errors.ResponseError{
-		StatusCode: 400,
-		Message:    "request error with different status code, expected: 200 but returned: 400 with error message: id is not a valid uuid",
-		CausedBy:   nil,
+		StatusCode:   400,
+		Message:      "request error with different status code, expected: 200 but returned: 400 with error message: id is not a valid uuid",
+		ErrorMessage: "id is not a valid uuid",
+		Method:       http.MethodGet,
+		URL:          configs.Properties().BaseAPIURL + "/organisation/accounts/wrong%20id%20value",
+		CausedBy:     nil,
 	}
 
+	assert.IsType(t, errors.ResponseError{}, actErr)
+	expErr.RequestID = actErr.(errors.ResponseError).RequestID
+
 	assert.EqualValues(t, expErr, actErr)
 
 	acc.ID = tempID
 	deleteAccount(a, acc)
 }
+
+func TestAccountIteration(t *testing.T) {
+
+	expAccList := []model.Account{
+		readFileAsAccount("data/account.json"),
+		readFileAsAccount("data/second-account.json"),
+		readFileAsAccount("data/third-account.json"),
+		readFileAsAccount("data/fourth-account.json")}
+
+	a := service.Account{}
+
+	for _, acc := range expAccList {
+		_, err := a.Create(acc)
+		if err != nil {
+			log.Fatalf("fail to create account resource: %s", err)
+		}
+	}
+
+	actAccList := []model.Account{}
+	it := a.Iterate(context.Background(), 3)
+	for it.Next() {
+		actAccList = append(actAccList, it.Value())
+	}
+
+	assert.Nil(t, it.Err())
+	assert.EqualValues(t, len(expAccList), len(actAccList))
+
+	for i, acc := range expAccList {
+		assert.EqualValues(t, acc.ID, actAccList[i].ID)
+		assert.EqualValues(t, acc.Attributes, actAccList[i].Attributes)
+	}
+
+	for _, acc := range expAccList {
+		deleteAccount(a, acc)
+	}
+}
+
+func TestAccountIterationWithFilter(t *testing.T) {
+	accList := []model.Account{
+		readFileAsAccount("data/account.json"),
+		readFileAsAccount("data/second-account.json"),
+		readFileAsAccount("data/third-account.json"),
+		readFileAsAccount("data/fourth-account.json")}
+
+	a := service.Account{}
+	createAccounts(a, accList)
+
+	actAccList := []model.Account{}
+	it := a.IterateWith(context.Background(), service.ListOptions{
+		PageSize: 1,
+		Filter:   service.AccountFilter{Country: []string{"GP"}},
+	})
+	for it.Next() {
+		actAccList = append(actAccList, it.Value())
+	}
+
+	assert.Nil(t, it.Err())
+	assert.EqualValues(t, 3, len(actAccList))
+	for i, acc := range accList[1:] {
+		assert.EqualValues(t, acc.ID, actAccList[i].ID)
+	}
+
+	for _, acc := range accList {
+		deleteAccount(a, acc)
+	}
+}
+
+func TestFailAccountListingByMissingId(t *testing.T) {
+	a := service.Account{}
+
+	_, actErr := a.ListBy("f3a1ad2c-4b8d-4a06-9c9b-4f2f0f0f0f0f")
+
+	assert.True(t, goerrors.Is(actErr, errors.ErrNotFound))
+	assert.False(t, goerrors.Is(actErr, errors.ErrConflict))
+
+	var resErr errors.ResponseError
+	assert.True(t, goerrors.As(actErr, &resErr))
+	assert.EqualValues(t, http.MethodGet, resErr.Method)
+}
+
+func TestAccountListingWithFilterAndSort(t *testing.T) {
+
+	expAccList := []model.Account{
+		readFileAsAccount("data/account.json"),
+		readFileAsAccount("data/second-account.json"),
+		readFileAsAccount("data/third-account.json"),
+		readFileAsAccount("data/fourth-account.json")}
+
+	a := service.Account{}
+	createAccounts(a, expAccList)
+
+	accResList, err := a.ListWith(context.Background(), service.ListOptions{
+		Sort:   []string{"-id"},
+		Filter: service.AccountFilter{Country: []string{"GP"}},
+	})
+	if err != nil {
+		log.Fatalf("fail to list accounts: %s", err)
+	}
+
+	actIDs := []string{}
+	for _, res := range accResList {
+		assert.EqualValues(t, "GP", res.(model.Account).Attributes.Country)
+		actIDs = append(actIDs, res.(model.Account).ID)
+	}
+	assert.EqualValues(t, []string{expAccList[2].ID, expAccList[1].ID, expAccList[3].ID}, actIDs)
+
+	accResList, err = a.ListWith(context.Background(), service.ListOptions{
+		PageNumber: 1,
+		PageSize:   1,
+		Filter:     service.AccountFilter{Country: []string{"GB", "GP"}, CustomerID: []string{"987788"}},
+	})
+	if err != nil {
+		log.Fatalf("fail to list accounts: %s", err)
+	}
+
+	assert.EqualValues(t, 1, len(accResList))
+	assert.EqualValues(t, expAccList[1].ID, accResList[0].(model.Account).ID)
+
+	for _, acc := range expAccList {
+		deleteAccount(a, acc)
+	}
+}
+
+func TestListOptionsQueryEncoding(t *testing.T) {
+	opts := service.ListOptions{
+		PageNumber: 2,
+		Sort:       []string{"-created_on"},
+		Filter:     service.AccountFilter{BankID: []string{"400302"}, Iban: []string{"GB33 BUKB"}},
+	}
+
+	assert.EqualValues(t,
+		"filter%5Bbank_id%5D=400302&filter%5Biban%5D=GB33+BUKB&page%5Bnumber%5D=2&page%5Bsize%5D=5&sort=-created_on",
+		opts.Values("5").Encode())
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	goerrors "errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func TestAccountUpdate(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	res, err := a.Create(acc)
+	if err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+
+	expAcc := *res.(*model.Account)
+	expAcc.Attributes.AlternativeBankAccountNames = []string{"Sam Holder"}
+
+	res, err = a.Update(expAcc)
+	if err != nil {
+		log.Fatalf("fail to update account resource: %s", err)
+	}
+
+	actAcc := res.(*model.Account)
+
+	assert.EqualValues(t, expAcc.ID, actAcc.ID)
+	assert.EqualValues(t, expAcc.Version+1, actAcc.Version)
+	assert.EqualValues(t, expAcc.Attributes.AlternativeBankAccountNames, actAcc.Attributes.AlternativeBankAccountNames)
+
+	assert.Nil(t, a.Delete(*actAcc))
+}
+
+func TestFailAccountUpdateWithOldVersion(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	a := service.Account{}
+
+	res, err := a.Create(acc)
+	if err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+
+	oldAcc := *res.(*model.Account)
+	if _, err = a.Update(oldAcc); err != nil {
+		log.Fatalf("fail to update account resource: %s", err)
+	}
+
+	_, actErr := a.Update(oldAcc)
+
+	assert.IsType(t, errors.VersionConflictError{}, actErr)
+	assert.True(t, goerrors.Is(actErr, errors.ErrConflict))
+	assert.EqualValues(t, oldAcc.Version, actErr.(errors.VersionConflictError).Version)
+
+	assert.Nil(t, a.DeleteLatest(acc.ID))
+}
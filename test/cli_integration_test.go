@@ -0,0 +1,75 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pancudaniel7/fake-api-client/configs"
+	"github.com/pancudaniel7/fake-api-client/internal/cli"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestCliAccountCommands(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	baseURL := "--base-url=" + configs.Properties().BaseAPIURL
+
+	code, out, _ := runCli("accounts", "create", "-f", "data/account.json", baseURL)
+	assert.EqualValues(t, cli.ExitOK, code)
+
+	actAcc := model.Account{}
+	assert.Nil(t, json.Unmarshal([]byte(out), &actAcc))
+	assert.EqualValues(t, acc.ID, actAcc.ID)
+
+	code, out, _ = runCli("accounts", "get", acc.ID, "--output", "yaml")
+	assert.EqualValues(t, cli.ExitOK, code)
+	assert.Contains(t, out, "id: "+acc.ID)
+
+	code, out, _ = runCli("accounts", "list", "--all", "--filter", "country=GB", "--output", "table")
+	assert.EqualValues(t, cli.ExitOK, code)
+	assert.True(t, strings.HasPrefix(out, "ID "))
+	assert.Contains(t, out, acc.ID)
+
+	code, _, errOut := runCli("accounts", "create", "-f", "data/account.json")
+	assert.EqualValues(t, cli.ExitConflict, code)
+	assert.Contains(t, errOut, "409")
+
+	code, _, _ = runCli("accounts", "delete", acc.ID, "--version", "0")
+	assert.EqualValues(t, cli.ExitOK, code)
+
+	code, _, _ = runCli("accounts", "get", acc.ID)
+	assert.EqualValues(t, cli.ExitNotFound, code)
+}
+
+func TestFailCliUsage(t *testing.T) {
+	code, _, errOut := runCli("accounts", "get")
+	assert.EqualValues(t, cli.ExitUsage, code)
+	assert.Contains(t, errOut, "Usage:")
+
+	code, _, _ = runCli("accounts", "create", "-f", "data/invalid-account.json")
+	assert.EqualValues(t, cli.ExitInvalid, code)
+
+	code, _, _ = runCli("accounts", "list", "--output", "xml")
+	assert.EqualValues(t, cli.ExitUsage, code)
+}
+
+func runCli(args ...string) (int, string, string) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := cli.Run("test", args, stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
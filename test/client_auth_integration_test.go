@@ -0,0 +1,168 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	goerrors "errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/auth"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/fakeapi"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccountCreationWithRSASignature(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("fail to generate rsa key: %s", err)
+	}
+
+	testSignedAccountCreation(t, key, key.Public())
+}
+
+func TestAccountCreationWithEd25519Signature(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatalf("fail to generate ed25519 key: %s", err)
+	}
+
+	testSignedAccountCreation(t, key, pub)
+}
+
+func TestFailAccountListingByIdWithWrongSignatureKey(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+
+	srv := newSignatureVerifierServer(t, pub)
+	defer srv.Close()
+
+	a := service.NewAccount(client.New(
+		client.WithBaseURL(srv.URL+fakeapi.BasePath),
+		client.WithAuthenticator(auth.NewHTTPSignature("key-id", key))))
+
+	_, err := a.ListBy("3732611e-3106-440a-a50c-96d1db2a6d6a")
+
+	assert.True(t, goerrors.Is(err, errors.ErrUnauthorized))
+}
+
+func TestFailAccountListingByIdWithStaleSignatureDate(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(rand.Reader)
+
+	srv := newSignatureVerifierServer(t, pub)
+	defer srv.Close()
+
+	s := auth.NewHTTPSignature("key-id", key)
+	s.Now = func() time.Time { return time.Now().Add(-2 * auth.DefaultMaxClockSkew) }
+	a := service.NewAccount(client.New(
+		client.WithBaseURL(srv.URL+fakeapi.BasePath),
+		client.WithAuthenticator(s)))
+
+	_, err := a.ListBy("3732611e-3106-440a-a50c-96d1db2a6d6a")
+
+	assert.True(t, goerrors.Is(err, errors.ErrUnauthorized))
+}
+
+func TestFailSignatureVerificationWithoutRequiredHeaders(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(rand.Reader)
+
+	for _, h := range []string{"(request-target)", "host", "date", "digest"} {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/v1/organisation/accounts",
+			strings.NewReader(`{"data":{}}`))
+		if err := auth.NewHTTPSignature("key-id", key).Authenticate(req); err != nil {
+			log.Fatalf("fail to sign request: %s", err)
+		}
+		req.Header.Set("Authorization", strings.Replace(req.Header.Get("Authorization"), h+" ", "", 1))
+		req.Header.Set("Authorization", strings.Replace(req.Header.Get("Authorization"), " "+h, "", 1))
+
+		_, err := auth.VerifyHTTPSignature(req, pub, auth.DefaultMaxClockSkew)
+
+		assert.EqualError(t, err, h+" header is not signed")
+	}
+}
+
+func TestFailSignatureVerificationWithTamperedBody(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(rand.Reader)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/v1/organisation/accounts",
+		strings.NewReader(`{"data":{}}`))
+	if err := auth.NewHTTPSignature("key-id", key).Authenticate(req); err != nil {
+		log.Fatalf("fail to sign request: %s", err)
+	}
+	req.Body = io.NopCloser(strings.NewReader(`{"data":{"id":"1"}}`))
+
+	_, err := auth.VerifyHTTPSignature(req, pub, auth.DefaultMaxClockSkew)
+
+	assert.EqualError(t, err, "request digest does not match the body")
+}
+
+func TestAccountListingByIdWithBearerToken(t *testing.T) {
+	var actAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actAuth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	a := service.NewAccount(client.New(
+		client.WithBaseURL(srv.URL),
+		client.WithAuthenticator(auth.Bearer{Token: "token"})))
+
+	_, err := a.ListBy("3732611e-3106-440a-a50c-96d1db2a6d6a")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Bearer token", actAuth)
+}
+
+func testSignedAccountCreation(t *testing.T, key crypto.Signer, pub crypto.PublicKey) {
+	srv := newSignatureVerifierServer(t, pub)
+	defer srv.Close()
+
+	a := service.NewAccount(client.New(
+		client.WithBaseURL(srv.URL+fakeapi.BasePath),
+		client.WithAuthenticator(auth.NewHTTPSignature("key-id", key))))
+
+	acc := readFileAsAccount("data/account.json")
+	_, err := a.Create(acc)
+	assert.Nil(t, err)
+	assert.Nil(t, a.DeleteBy(acc.ID))
+}
+
+// newSignatureVerifierServer function returns a fakeapi server that
+// responds with 401 status code if the request signature is not valid.
+func newSignatureVerifierServer(t *testing.T, pub crypto.PublicKey) *httptest.Server {
+	fake := fakeapi.NewServer()
+	t.Cleanup(fake.Close)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if keyID, err := auth.VerifyHTTPSignature(r, pub, auth.DefaultMaxClockSkew); err != nil || keyID != "key-id" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error_message":"invalid signature"}`))
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	goerrors "errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAccountListingCircuitBreaker(t *testing.T) {
+	var down, calls int32 = 1, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error_message":"unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	mu := sync.Mutex{}
+	changes := []client.CircuitState{}
+	b := client.NewCircuitBreaker(client.CircuitBreakerSettings{
+		ConsecutiveFailures: 2,
+		CoolDown:            50 * time.Millisecond,
+		OnStateChange: func(from, to client.CircuitState) {
+			mu.Lock()
+			changes = append(changes, to)
+			mu.Unlock()
+		},
+	})
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.URL), client.WithCircuitBreaker(b)))
+
+	for i := 0; i < 2; i++ {
+		_, err := a.List("0", "1")
+		assert.True(t, goerrors.Is(err, errors.ErrServer))
+	}
+	assert.EqualValues(t, client.CircuitOpen, b.State())
+
+	_, err := a.List("0", "1")
+	assert.True(t, goerrors.Is(err, errors.ErrCircuitOpen))
+	assert.True(t, err.(errors.CircuitOpenError).RetryAfter > 0)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.EqualValues(t, client.CircuitHalfOpen, b.State())
+
+	_, err = a.List("0", "1")
+	assert.True(t, goerrors.Is(err, errors.ErrServer))
+	assert.EqualValues(t, client.CircuitOpen, b.State())
+
+	atomic.StoreInt32(&down, 0)
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = a.List("0", "1")
+	assert.Nil(t, err)
+	assert.EqualValues(t, client.CircuitClosed, b.State())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.EqualValues(t, []client.CircuitState{
+		client.CircuitOpen, client.CircuitHalfOpen, client.CircuitOpen,
+		client.CircuitHalfOpen, client.CircuitClosed}, changes)
+}
+
+func TestCircuitBreakerFailureRatio(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1)%2 == 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"error_message":"bad gateway"}`))
+			return
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	b := client.NewCircuitBreaker(client.CircuitBreakerSettings{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		Window:       time.Minute,
+		CoolDown:     time.Minute,
+	})
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.URL), client.WithCircuitBreaker(b)))
+
+	for i := 0; i < 3; i++ {
+		a.List("0", "1")
+	}
+	assert.EqualValues(t, client.CircuitClosed, b.State())
+
+	a.List("0", "1")
+	assert.EqualValues(t, client.CircuitOpen, b.State())
+	assert.EqualValues(t, "open", b.State().String())
+}
+
+func TestCircuitBreakerRejectsBeforeRateLimiter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error_message":"unavailable"}`))
+	}))
+	defer srv.Close()
+
+	b := client.NewCircuitBreaker(client.CircuitBreakerSettings{ConsecutiveFailures: 1, CoolDown: time.Minute})
+	l := client.NewRateLimiter(0.1, 3)
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.URL),
+		client.WithCircuitBreaker(b), client.WithRateLimiter(l)))
+
+	_, err := a.List("0", "1")
+	assert.True(t, goerrors.Is(err, errors.ErrServer))
+	assert.EqualValues(t, client.CircuitOpen, b.State())
+
+	for i := 0; i < 3; i++ {
+		_, err = a.List("0", "1")
+		assert.True(t, goerrors.Is(err, errors.ErrCircuitOpen))
+	}
+	assert.True(t, l.State().Tokens >= 2)
+}
+
+func TestCircuitBreakerReleasesTrialCanceledByRateLimiter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error_message":"unavailable"}`))
+	}))
+	defer srv.Close()
+
+	b := client.NewCircuitBreaker(client.CircuitBreakerSettings{ConsecutiveFailures: 1, CoolDown: 20 * time.Millisecond})
+	l := client.NewRateLimiter(0.1, 1)
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.URL),
+		client.WithCircuitBreaker(b), client.WithRateLimiter(l)))
+
+	_, err := a.List("0", "1")
+	assert.True(t, goerrors.Is(err, errors.ErrServer))
+	time.Sleep(30 * time.Millisecond)
+	assert.EqualValues(t, client.CircuitHalfOpen, b.State())
+
+	// both trial requests wait for a token, the second one is allowed
+	// only if the first one gave back its trial slot.
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err = a.ListCtx(ctx, "0", "1")
+		cancel()
+		assert.True(t, goerrors.Is(err, context.DeadlineExceeded))
+	}
+	assert.EqualValues(t, client.CircuitHalfOpen, b.State())
+}
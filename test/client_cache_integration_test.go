@@ -0,0 +1,227 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	goerrors "errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/fakeapi"
+	"github.com/pancudaniel7/fake-api-client/pkg/metrics"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAccountListingByIdFromCache(t *testing.T) {
+	expAcc := readFileAsAccount("data/account.json")
+	cache := client.NewCache(time.Minute, 10)
+	m := metrics.NewPrometheus(metrics.DefaultNamespace, nil)
+	a := service.NewAccount(client.New(client.WithCache(cache), client.WithMetrics(m)))
+
+	createAccounts(a, []model.Account{expAcc})
+
+	for i := 0; i < 3; i++ {
+		res, err := a.ListBy(expAcc.ID)
+		if err != nil {
+			log.Fatalf("fail to list account resource: %s", err)
+		}
+		assert.EqualValues(t, expAcc.Attributes, res.(*model.Account).Attributes)
+	}
+
+	assert.EqualValues(t, client.CacheStats{Hits: 2, Misses: 1, Entries: 1}, cache.Stats())
+	assert.EqualValues(t, 1, m.Requests("account.fetch", "GET", 200))
+	assert.EqualValues(t, 2, m.CacheHits("account.fetch", "GET", 200))
+
+	deleteAccount(a, expAcc)
+}
+
+func TestAccountListingByIdRevalidatesCache(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+
+	expAcc := readFileAsAccount("data/account.json")
+	cache := client.NewCache(0, 10)
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.BaseURL()), client.WithCache(cache)))
+
+	createAccounts(a, []model.Account{expAcc})
+
+	for i := 0; i < 3; i++ {
+		res, err := a.ListBy(expAcc.ID)
+		if err != nil {
+			log.Fatalf("fail to list account resource: %s", err)
+		}
+		assert.EqualValues(t, expAcc.Attributes, res.(*model.Account).Attributes)
+	}
+
+	assert.EqualValues(t, client.CacheStats{Misses: 1, Revalidations: 2, Entries: 1}, cache.Stats())
+}
+
+func TestAccountCacheInvalidationOnUpdateAndDelete(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	cache := client.NewCache(time.Minute, 10)
+	a := service.NewAccount(client.New(client.WithCache(cache)))
+
+	createAccounts(a, []model.Account{acc})
+
+	res, err := a.ListBy(acc.ID)
+	if err != nil {
+		log.Fatalf("fail to list account resource: %s", err)
+	}
+
+	updAcc := *res.(*model.Account)
+	updAcc.Attributes.AlternativeBankAccountNames = []string{"Sam Holder"}
+	if _, err = a.Update(updAcc); err != nil {
+		log.Fatalf("fail to update account resource: %s", err)
+	}
+
+	res, err = a.ListBy(acc.ID)
+	if err != nil {
+		log.Fatalf("fail to list account resource: %s", err)
+	}
+	actAcc := res.(*model.Account)
+	assert.EqualValues(t, 1, actAcc.Version)
+	assert.EqualValues(t, updAcc.Attributes.AlternativeBankAccountNames, actAcc.Attributes.AlternativeBankAccountNames)
+
+	assert.Nil(t, a.Delete(*actAcc))
+
+	_, err = a.ListBy(acc.ID)
+	assert.True(t, goerrors.Is(err, errors.ErrNotFound))
+	assert.EqualValues(t, client.CacheStats{Misses: 3}, cache.Stats())
+}
+
+func TestAccountCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	accList := []model.Account{
+		readFileAsAccount("data/account.json"),
+		readFileAsAccount("data/second-account.json"),
+	}
+	cache := client.NewCache(time.Minute, 1)
+	a := service.NewAccount(client.New(client.WithCache(cache)))
+
+	createAccounts(a, accList)
+
+	for _, id := range []string{accList[0].ID, accList[0].ID, accList[1].ID, accList[0].ID} {
+		if _, err := a.ListBy(id); err != nil {
+			log.Fatalf("fail to list account resource: %s", err)
+		}
+	}
+
+	assert.EqualValues(t, client.CacheStats{Hits: 1, Misses: 3, Evictions: 2, Entries: 1}, cache.Stats())
+
+	cache.Purge()
+	assert.EqualValues(t, 0, cache.Stats().Entries)
+
+	for _, acc := range accList {
+		deleteAccount(a, acc)
+	}
+}
+
+func TestAccountListingByIdRevalidatesEvictedCacheEntry(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+
+	expAcc := readFileAsAccount("data/account.json")
+	cache := client.NewCache(0, 10)
+	evictingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.Header.Get("If-None-Match")) != 0 {
+			cache.Purge()
+		}
+		srv.ServeHTTP(w, r)
+	}))
+	defer evictingSrv.Close()
+
+	a := service.NewAccount(client.New(client.WithBaseURL(evictingSrv.URL+fakeapi.BasePath), client.WithCache(cache)))
+
+	createAccounts(a, []model.Account{expAcc})
+
+	for i := 0; i < 2; i++ {
+		res, err := a.ListBy(expAcc.ID)
+		if err != nil {
+			log.Fatalf("fail to list account resource: %s", err)
+		}
+		assert.EqualValues(t, expAcc.Attributes, res.(*model.Account).Attributes)
+	}
+
+	// the revalidated response is returned but it is not cached again after Purge.
+	assert.EqualValues(t, client.CacheStats{Misses: 1, Revalidations: 1}, cache.Stats())
+}
+
+func TestAccountListingByIdCacheHitTracing(t *testing.T) {
+	expAcc := readFileAsAccount("data/account.json")
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	a := service.NewAccount(client.New(client.WithCache(client.NewCache(time.Minute, 10)), client.WithTracerProvider(tp)))
+
+	createAccounts(a, []model.Account{expAcc})
+	for i := 0; i < 2; i++ {
+		if _, err := a.ListBy(expAcc.ID); err != nil {
+			log.Fatalf("fail to list account resource: %s", err)
+		}
+	}
+
+	spans := exp.GetSpans()
+	hit := spans[len(spans)-1]
+	// create and fetch have an operation and an attempt span, the cache hit has only the operation span.
+	assert.EqualValues(t, "Account.Fetch", hit.Name)
+	assert.Contains(t, hit.Attributes, attribute.Bool("fake_api_client.cache_hit", true))
+	assert.EqualValues(t, 5, len(spans))
+
+	deleteAccount(a, expAcc)
+}
+
+func TestAccountCacheIgnoresFetchFinishedAfterDelete(t *testing.T) {
+	srv := fakeapi.NewServer()
+	defer srv.Close()
+
+	fetched, deleted := make(chan struct{}), make(chan struct{})
+	slowSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			srv.ServeHTTP(w, r)
+			return
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, r)
+		close(fetched)
+		<-deleted
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	}))
+	defer slowSrv.Close()
+
+	acc := readFileAsAccount("data/account.json")
+	cache := client.NewCache(time.Minute, 10)
+	a := service.NewAccount(client.New(client.WithBaseURL(slowSrv.URL+fakeapi.BasePath), client.WithCache(cache)))
+	createAccounts(a, []model.Account{acc})
+
+	done := make(chan error)
+	go func() {
+		_, err := a.ListBy(acc.ID)
+		done <- err
+	}()
+	<-fetched
+	deleteAccount(a, acc)
+	close(deleted)
+
+	assert.Nil(t, <-done)
+	assert.EqualValues(t, 0, cache.Stats().Entries)
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"github.com/pancudaniel7/fake-api-client/configs"
+	"github.com/pancudaniel7/fake-api-client/pkg/cassette"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc type is used to create http transports from functions.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip method calls f function.
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAccountCassetteRecordAndReplay(t *testing.T) {
+	expAcc := readFileAsAccount("data/account.json")
+	path := filepath.Join(t.TempDir(), "account.json")
+
+	rec, err := cassette.New(path, cassette.ModeRecord, nil)
+	if err != nil {
+		log.Fatalf("fail to create cassette: %s", err)
+	}
+	auth := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req.Header.Set("Authorization", "Bearer secret-token")
+		return rec.RoundTrip(req)
+	})
+	a := service.NewAccount(client.New(
+		client.WithBaseURL(configs.Properties().BaseAPIURL),
+		client.WithTransport(auth)))
+
+	if _, err = a.Create(expAcc); err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+	deleteAccount(a, expAcc)
+	if err = rec.Save(); err != nil {
+		log.Fatalf("fail to save cassette: %s", err)
+	}
+
+	b, _ := os.ReadFile(path)
+	assert.False(t, strings.Contains(string(b), "secret-token"))
+	assert.True(t, strings.Contains(string(b), cassette.RedactedValue))
+
+	rep, err := cassette.New(path, cassette.ModeReplay, nil)
+	if err != nil {
+		log.Fatalf("fail to load cassette: %s", err)
+	}
+	a = service.NewAccount(client.New(
+		client.WithBaseURL("http://cassette.invalid/v1"),
+		client.WithTransport(rep)))
+
+	res, err := a.Create(expAcc)
+	assert.Nil(t, err)
+	assert.EqualValues(t, expAcc.Attributes, res.(*model.Account).Attributes)
+	assert.Nil(t, a.DeleteBy(expAcc.ID))
+
+	_, err = a.ListBy(expAcc.ID)
+	assert.NotNil(t, err)
+}
+
+// TestAccountListingByIdFromCassette test replays a cassette recorded against fakeapi.Server,
+// run it with CASSETTE_MODE=record and the integration build tag to record it against fake-api.
+func TestAccountListingByIdFromCassette(t *testing.T) {
+	expAcc := readFileAsAccount("data/third-account.json")
+
+	mode, err := cassette.ParseMode(os.Getenv("CASSETTE_MODE"))
+	if err != nil {
+		log.Fatalf("fail to parse cassette mode: %s", err)
+	}
+	cas, err := cassette.New("data/cassettes/account-listing-by-id.json", mode, nil)
+	if err != nil {
+		log.Fatalf("fail to load cassette: %s", err)
+	}
+	defer cas.Save()
+
+	a := service.NewAccount(client.New(
+		client.WithBaseURL(configs.Properties().BaseAPIURL),
+		client.WithTransport(cas)))
+
+	if mode == cassette.ModeRecord {
+		if _, err = a.Create(expAcc); err != nil {
+			log.Fatalf("fail to create account resource: %s", err)
+		}
+		defer deleteAccount(service.Account{}, expAcc)
+	}
+
+	res, err := a.ListBy(expAcc.ID)
+	if err != nil {
+		log.Fatalf("fail to list account: %s", err)
+	}
+
+	assert.EqualValues(t, expAcc.ID, res.(*model.Account).ID)
+	assert.EqualValues(t, expAcc.Attributes, res.(*model.Account).Attributes)
+}
+
+func TestCassetteDoesNotModifyRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.Write(b)
+	}))
+	defer srv.Close()
+
+	for _, body := range []io.Reader{strings.NewReader(`{"a":1}`), io.MultiReader(strings.NewReader(`{"a":1}`))} {
+		cas := &cassette.Transport{Mode: cassette.ModeRecord}
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, body)
+		req.Header.Set("Authorization", "Bearer secret-token")
+		reqBody := req.Body
+
+		res, err := cas.RoundTrip(req)
+		if err != nil {
+			log.Fatalf("fail to send request: %s", err)
+		}
+		b, _ := io.ReadAll(res.Body)
+
+		assert.True(t, reqBody == req.Body)
+		assert.EqualValues(t, `{"a":1}`, string(b))
+	}
+}
+
+func TestZeroCassetteRedactsHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "zero.json")
+	cas := &cassette.Transport{Path: path, Mode: cassette.ModeRecord}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	if _, err := cas.RoundTrip(req); err != nil {
+		log.Fatalf("fail to send request: %s", err)
+	}
+	if err := cas.Save(); err != nil {
+		log.Fatalf("fail to save cassette: %s", err)
+	}
+
+	b, _ := os.ReadFile(path)
+	assert.False(t, strings.Contains(string(b), "secret-token"))
+
+	_, err := (&cassette.Transport{}).RoundTrip(req)
+	assert.NotNil(t, err)
+}
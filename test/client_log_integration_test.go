@@ -0,0 +1,112 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestAccountCreationLogging(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	a := service.NewAccount(client.New(client.WithLogger(logger)))
+
+	if _, err := a.Create(acc); err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+	_, err := a.Create(acc)
+	assert.NotNil(t, err)
+
+	logs := readLogs(buf)
+	assert.EqualValues(t, 2, len(logs))
+
+	assert.EqualValues(t, "DEBUG", logs[0]["level"])
+	assert.EqualValues(t, "POST", logs[0]["method"])
+	assert.EqualValues(t, 201, logs[0]["status"])
+	assert.EqualValues(t, 1, logs[0]["attempt"])
+	assert.Nil(t, logs[0]["request_body"])
+
+	assert.EqualValues(t, "ERROR", logs[1]["level"])
+	assert.EqualValues(t, 409, logs[1]["status"])
+	assert.Contains(t, logs[1]["response_body"], "duplicate constraint")
+	assert.Contains(t, logs[1]["request_body"], `"iban":"[REDACTED]"`)
+	assert.NotContains(t, logs[1]["request_body"], acc.Attributes.Iban)
+
+	deleteAccount(a, acc)
+}
+
+func TestAccountFailuresOnlyLogging(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelError}))
+	a := service.NewAccount(client.New(client.WithLogging(client.Logging{Logger: logger, MaxBodySize: 10})))
+
+	if _, err := a.Create(acc); err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+	_, err := a.ListBy("wrong id value")
+	assert.NotNil(t, err)
+
+	logs := readLogs(buf)
+	assert.EqualValues(t, 1, len(logs))
+	assert.EqualValues(t, "GET", logs[0]["method"])
+	assert.True(t, strings.HasSuffix(logs[0]["response_body"].(string), "...(truncated)"))
+
+	deleteAccount(a, acc)
+}
+
+func TestAccountLoggingRedactsDefaultFields(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelError}))
+	a := service.NewAccount(client.New(client.WithLogging(client.Logging{Logger: logger})))
+
+	if _, err := a.Create(acc); err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+	_, err := a.Create(acc)
+	assert.NotNil(t, err)
+
+	logs := readLogs(buf)
+	assert.EqualValues(t, 1, len(logs))
+	assert.Contains(t, logs[0]["request_body"], `"iban":"[REDACTED]"`)
+	assert.Contains(t, logs[0]["request_body"], `"account_number":"[REDACTED]"`)
+	assert.NotContains(t, logs[0]["request_body"], acc.Attributes.Iban)
+	assert.NotContains(t, logs[0]["request_body"], acc.Attributes.AccountNumber)
+
+	deleteAccount(a, acc)
+}
+
+func readLogs(buf *bytes.Buffer) []map[string]interface{} {
+	logs := []map[string]interface{}{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		l := map[string]interface{}{}
+		if err := dec.Decode(&l); err != nil {
+			log.Fatalf("fail to decode log: %s", err)
+		}
+		logs = append(logs, l)
+	}
+	return logs
+}
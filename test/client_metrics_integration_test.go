@@ -0,0 +1,89 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/metrics"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccountRequestMetrics(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	m := metrics.NewPrometheus("", []float64{1, 60})
+	a := service.NewAccount(client.New(client.WithMetrics(m)))
+
+	if _, err := a.Create(acc); err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+	_, err := a.Create(acc)
+	assert.NotNil(t, err)
+	if _, err = a.List("", ""); err != nil {
+		log.Fatalf("fail to list accounts: %s", err)
+	}
+	_, err = a.ListBy("wrong id value")
+	assert.NotNil(t, err)
+
+	assert.EqualValues(t, 1, m.Requests("account.create", http.MethodPost, 201))
+	assert.EqualValues(t, 1, m.Requests("account.create", http.MethodPost, 409))
+	assert.EqualValues(t, 1, m.Errors("account.create", http.MethodPost, 409))
+	assert.EqualValues(t, 0, m.Errors("account.create", http.MethodPost, 201))
+	assert.EqualValues(t, 1, m.Requests("account.list", http.MethodGet, 200))
+	assert.EqualValues(t, 1, m.Errors("account.fetch", http.MethodGet, 400))
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+
+	assert.Contains(t, out, "# TYPE fake_api_client_requests_total counter\n")
+	assert.Contains(t, out, `fake_api_client_requests_total{operation="account.create",method="POST",status="201"} 1`)
+	assert.Contains(t, out, `fake_api_client_request_errors_total{operation="account.fetch",method="GET",status="400"} 1`)
+	assert.Contains(t, out, `fake_api_client_request_duration_seconds_bucket{operation="account.list",method="GET",status="200",le="60"} 1`)
+	assert.Contains(t, out, `fake_api_client_request_duration_seconds_count{operation="account.list",method="GET",status="200"} 1`)
+
+	deleteAccount(a, acc)
+}
+
+func TestPrometheusHistogramBuckets(t *testing.T) {
+	m := metrics.NewPrometheus("api", []float64{0.5, 0.1})
+	for _, d := range []time.Duration{50 * time.Millisecond, 200 * time.Millisecond, 2 * time.Second} {
+		m.ObserveRequest(client.RequestObservation{Operation: "account.fetch", Method: "GET", StatusCode: 200, Duration: d})
+	}
+
+	sb := &strings.Builder{}
+	m.WriteTo(sb)
+
+	assert.Contains(t, sb.String(), `api_request_duration_seconds_bucket{operation="account.fetch",method="GET",status="200",le="0.1"} 1`)
+	assert.Contains(t, sb.String(), `api_request_duration_seconds_bucket{operation="account.fetch",method="GET",status="200",le="0.5"} 2`)
+	assert.Contains(t, sb.String(), `api_request_duration_seconds_bucket{operation="account.fetch",method="GET",status="200",le="+Inf"} 3`)
+	assert.Contains(t, sb.String(), `api_request_duration_seconds_sum{operation="account.fetch",method="GET",status="200"} 2.25`)
+}
+
+func TestPrometheusLabelEscaping(t *testing.T) {
+	m := metrics.NewPrometheus("api", nil)
+	m.ObserveRequest(client.RequestObservation{Operation: "compte.créer\t\"x\"\\\n", Method: "GET", StatusCode: 200})
+
+	sb := &strings.Builder{}
+	m.WriteTo(sb)
+
+	assert.Contains(t, sb.String(), "api_requests_total{operation=\"compte.créer\t\\\"x\\\"\\\\\\n\",method=\"GET\",status=\"200\"} 1")
+}
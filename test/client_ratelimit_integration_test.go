@@ -0,0 +1,87 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAccountListingRateLimit(t *testing.T) {
+	l := client.NewRateLimiter(20, 2)
+	a := service.NewAccount(client.New(client.WithRateLimiter(l)))
+
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := a.List("0", "1")
+			assert.Nil(t, err)
+		}()
+	}
+
+	// the burst is spent by the first requests, the others wait holding negative tokens.
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, l.State().Tokens < 0)
+
+	wg.Wait()
+	assert.True(t, time.Since(start) >= 190*time.Millisecond)
+}
+
+func TestAccountOperationRateLimit(t *testing.T) {
+	l := client.NewRateLimiter(1000, 100)
+	l.SetOperationLimit("account.fetch", 10, 1)
+	a := service.NewAccount(client.New(client.WithRateLimiter(l)))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		a.ListBy("3732611e-3106-440a-a50c-96d1db2a6d6a")
+	}
+	assert.True(t, time.Since(start) >= 190*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		a.List("0", "1")
+	}
+
+	s := l.State()
+	_, limited := s.Operations["account.list"]
+	assert.False(t, limited)
+	assert.True(t, s.Tokens > 90)
+	assert.EqualValues(t, 10, s.Operations["account.fetch"].Rate)
+	assert.EqualValues(t, 1, s.Operations["account.fetch"].Burst)
+}
+
+func TestFailAccountListingRateLimitCanceled(t *testing.T) {
+	l := client.NewRateLimiter(0.1, 1)
+	a := service.NewAccount(client.New(client.WithRateLimiter(l)))
+
+	_, err := a.List("0", "1")
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = a.ListCtx(ctx, "0", "1")
+	assert.IsType(t, errors.CanceledError{}, err)
+	assert.True(t, l.State().Tokens >= 0)
+}
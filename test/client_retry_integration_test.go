@@ -0,0 +1,122 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/model"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAccountListingByIdRetry(t *testing.T) {
+	expAcc := readFileAsAccount("data/account.json")
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error_message":"unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"id":"` + expAcc.ID + `"}}`))
+	}))
+	defer srv.Close()
+
+	var attempts []client.Attempt
+	p := client.DefaultRetryPolicy()
+	p.OnAttempt = func(a client.Attempt) {
+		attempts = append(attempts, a)
+	}
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.URL), client.WithRetryPolicy(p)))
+
+	res, err := a.ListBy(expAcc.ID)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, expAcc.ID, res.(*model.Account).ID)
+	assert.EqualValues(t, 3, len(attempts))
+	assert.EqualValues(t, http.StatusServiceUnavailable, attempts[0].StatusCode)
+	assert.EqualValues(t, time.Duration(0), attempts[0].Delay)
+	assert.EqualValues(t, http.StatusOK, attempts[2].StatusCode)
+}
+
+func TestFailAccountListingByIdRetryExhausted(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"error_message":"bad gateway"}`))
+	}))
+	defer srv.Close()
+
+	p := client.DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.URL), client.WithRetryPolicy(p)))
+
+	_, err := a.ListBy("3732611e-3106-440a-a50c-96d1db2a6d6a")
+
+	assert.EqualValues(t, http.StatusBadGateway, err.(errors.ResponseError).StatusCode)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func TestAccountListingByIdRetryDelayWithoutMaxDelay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error_message":"unavailable"}`))
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	p := client.RetryPolicy{
+		MaxAttempts:     4,
+		BaseDelay:       time.Millisecond,
+		RetryableStatus: []int{http.StatusServiceUnavailable},
+		OnAttempt: func(a client.Attempt) {
+			delays = append(delays, a.Delay)
+		},
+	}
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.URL), client.WithRetryPolicy(p)))
+
+	_, err := a.ListBy("3732611e-3106-440a-a50c-96d1db2a6d6a")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 0}, delays)
+}
+
+func TestAccountCreationIsNotRetried(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error_message":"unavailable"}`))
+	}))
+	defer srv.Close()
+
+	p := client.DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	a := service.NewAccount(client.New(client.WithBaseURL(srv.URL), client.WithRetryPolicy(p)))
+
+	_, err := a.Create(readFileAsAccount("data/account.json"))
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
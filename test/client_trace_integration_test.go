@@ -0,0 +1,104 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAccountCreationTracing(t *testing.T) {
+	acc := readFileAsAccount("data/account.json")
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	a := service.NewAccount(client.New(client.WithTracerProvider(tp)))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	if _, err := a.CreateCtx(ctx, acc); err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+	parent.End()
+
+	spans := exp.GetSpans()
+	assert.EqualValues(t, 3, len(spans))
+
+	attempt, op := spans[0], spans[1]
+	assert.EqualValues(t, "HTTP POST", attempt.Name)
+	assert.EqualValues(t, "Account.Create", op.Name)
+	assert.EqualValues(t, op.SpanContext.SpanID(), attempt.Parent.SpanID())
+	assert.EqualValues(t, parent.SpanContext().SpanID(), op.Parent.SpanID())
+	assert.Contains(t, op.Attributes, attribute.Int("http.response.status_code", 201))
+	assert.Contains(t, op.Attributes, attribute.Int("fake_api_client.retry_count", 0))
+	assert.Contains(t, op.Attributes, attribute.String("fake_api_client.operation", "account.create"))
+
+	deleteAccount(a, acc)
+}
+
+func TestAccountListingByIdRetryTracing(t *testing.T) {
+	var calls int32
+	traceparents := make(chan string, 3)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("traceparent")
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error_message":"unavailable"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_message":"not found"}`))
+	}))
+	defer srv.Close()
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	p := client.DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	a := service.NewAccount(client.New(
+		client.WithBaseURL(srv.URL),
+		client.WithRetryPolicy(p),
+		client.WithTracerProvider(tp)))
+
+	_, err := a.ListBy("3732611e-3106-440a-a50c-96d1db2a6d6a")
+	assert.NotNil(t, err)
+
+	spans := exp.GetSpans()
+	assert.EqualValues(t, 4, len(spans))
+
+	op := spans[3]
+	assert.EqualValues(t, "Account.Fetch", op.Name)
+	assert.EqualValues(t, codes.Error, op.Status.Code)
+	assert.Contains(t, op.Attributes, attribute.Int("fake_api_client.retry_count", 2))
+	assert.Contains(t, op.Attributes, attribute.Int("http.response.status_code", 404))
+
+	for i := 0; i < 3; i++ {
+		assert.EqualValues(t, "HTTP GET", spans[i].Name)
+		assert.Contains(t, spans[i].Attributes, attribute.Int("http.request.resend_count", i))
+		assert.EqualValues(t, "00-"+spans[i].SpanContext.TraceID().String()+"-"+spans[i].SpanContext.SpanID().String()+"-01",
+			<-traceparents)
+	}
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.response.status_code", 503))
+}
@@ -0,0 +1,49 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"github.com/pancudaniel7/fake-api-client/pkg/fakeapi"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"log"
+	"os"
+	"testing"
+)
+
+// testOrganisationID is the organisation id of the test data accounts.
+const testOrganisationID = "3e72d92e-f30d-4bb1-84f8-b9fa04f6e748"
+
+// TestMain function runs the tests against the real fake-api service
+// when the integration build tag is given, otherwise the tests are run
+// against an in-memory fakeapi server.
+// Before the tests are run against the real service the test organisation
+// accounts leaked by previous runs are purged.
+func TestMain(m *testing.M) {
+	if integration {
+		_, err := service.Account{}.PurgeOrganisation(context.Background(), testOrganisationID, nil, service.DeleteOptions{})
+		if err != nil {
+			log.Fatalf("fail to purge test organisation accounts: %s", err)
+		}
+		os.Exit(m.Run())
+	}
+
+	srv := fakeapi.NewServer()
+	os.Setenv("BASE_API_URL", srv.BaseURL())
+
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
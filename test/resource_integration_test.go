@@ -0,0 +1,56 @@
+// Copyright 2019 Form3 Financial Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	goerrors "errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/client"
+	"github.com/pancudaniel7/fake-api-client/pkg/errors"
+	"github.com/pancudaniel7/fake-api-client/pkg/service"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func TestGenericAccountResource(t *testing.T) {
+	expAcc := readFileAsAccount("data/account.json")
+	r := service.Accounts(client.Default())
+	ctx := context.Background()
+
+	created, err := r.Create(ctx, expAcc)
+	if err != nil {
+		log.Fatalf("fail to create account resource: %s", err)
+	}
+	assert.EqualValues(t, expAcc.ID, created.ID)
+
+	fetched, err := r.Fetch(ctx, expAcc.ID)
+	if err != nil {
+		log.Fatalf("fail to fetch account resource: %s", err)
+	}
+	assert.EqualValues(t, expAcc.Attributes, fetched.Attributes)
+
+	list, err := r.List(ctx, "", "")
+	if err != nil {
+		log.Fatalf("fail to list account resources: %s", err)
+	}
+	assert.EqualValues(t, 1, len(list))
+	assert.EqualValues(t, expAcc.ID, list[0].ID)
+
+	assert.Nil(t, r.Delete(ctx, fetched.ID, fetched.Version))
+
+	_, err = r.Fetch(ctx, expAcc.ID)
+	assert.True(t, goerrors.Is(err, errors.ErrNotFound))
+}